@@ -1,10 +1,44 @@
 package domain
 
+import "time"
+
 const (
 	// DefaultTimeout is a default timeout of http request.
 	DefaultTimeout = 10
 	// DefaultMaxRequestCount is a default max parallel http request count.
 	DefaultMaxRequestCount = 10
+	// DefaultRetryBackoff is a default delay before the first retry.
+	DefaultRetryBackoff = time.Second
+	// DefaultRetryMaxDelay is a default max delay before a retry.
+	DefaultRetryMaxDelay = 30 * time.Second
+	// DefaultRetryAfterMaxWait is a default max total wait for the Retry-After header per url.
+	DefaultRetryAfterMaxWait = time.Minute
+	// DefaultCacheFile is a default path of the cache file.
+	DefaultCacheFile = ".durl-cache"
+	// DefaultCacheTTL is a default period while the cached result of a live url is used.
+	DefaultCacheTTL = 24 * time.Hour
+	// DefaultCacheFailureTTL is a default period while the cached result of a dead url is used.
+	DefaultCacheFailureTTL = time.Hour
+	// FormatText is a output format which outputs dead urls as text.
+	FormatText = "text"
+	// FormatJSON is a output format which outputs all results as a JSON document.
+	FormatJSON = "json"
+	// FormatNDJSON is a output format which outputs a JSON line per a checked url.
+	FormatNDJSON = "ndjson"
+	// FormatSARIF is a output format which outputs dead urls as a SARIF 2.1.0 log.
+	FormatSARIF = "sarif"
+	// FormatJUnit is a output format which outputs all results as a JUnit XML report.
+	FormatJUnit = "junit"
+	// DefaultMaxRedirects is a default max number of redirects which are followed.
+	DefaultMaxRedirects = 10
+	// RedirectsFollow is a redirects policy to follow permanent redirects silently.
+	RedirectsFollow = "follow"
+	// RedirectsWarn is a redirects policy to warn permanent redirects.
+	RedirectsWarn = "warn"
+	// RedirectsError is a redirects policy to regard permanent redirects as dead.
+	RedirectsError = "error"
+	// FileSourceGit is a file_source to check files which are tracked or not ignored by git.
+	FileSourceGit = "git"
 	// CfgTpl is a template of configuration file.
 	CfgTpl = `
 ---
@@ -17,9 +51,43 @@ http_method: head,get
 max_request_count: 10
 max_failed_request_count: 5
 http_request_timeout: 10
+include: []
+exclude:
+- .git
+- node_modules
+- vendor
 `
 )
 
-var IgnoreHosts = []string{ //nolint:gochecknoglobals
+// ErrorKind is the category of the failure of checking a url.
+const (
+	// ErrorKindHTTPClientError means the status code is 4xx.
+	ErrorKindHTTPClientError = "http_4xx"
+	// ErrorKindHTTPServerError means the status code is 5xx.
+	ErrorKindHTTPServerError = "http_5xx"
+	// ErrorKindHTTPStatus means the status code is neither 2xx, 4xx nor 5xx.
+	ErrorKindHTTPStatus = "http_status"
+	// ErrorKindDNS means it is failed to resolve the host.
+	ErrorKindDNS = "dns"
+	// ErrorKindTLS means it is failed to establish a TLS connection.
+	ErrorKindTLS = "tls"
+	// ErrorKindTimeout means the request is timed out.
+	ErrorKindTimeout = "timeout"
+	// ErrorKindConnection means it is failed to connect to the server.
+	ErrorKindConnection = "connection"
+	// ErrorKindRedirect means the url is redirected too many times or permanently redirected with the redirects policy "error".
+	ErrorKindRedirect = "redirect"
+	// ErrorKindNotFound means the linked local file or directory doesn't exist.
+	ErrorKindNotFound = "not_found"
+	// ErrorKindAnchor means the anchor of the url isn't found in the page.
+	ErrorKindAnchor = "anchor"
+	// ErrorKindInvalid means the url or the configuration is invalid.
+	ErrorKindInvalid = "invalid"
+	// ErrorKindOther means the other failure.
+	ErrorKindOther = "other"
+)
+
+// DefaultIgnoreHosts are hosts which aren't checked unless default_ignore_hosts is false.
+var DefaultIgnoreHosts = []string{ //nolint:gochecknoglobals
 	"localhost", "example.com", "example.org", "example.net", "127.0.0.1",
 }
@@ -3,7 +3,10 @@ package domain
 import (
 	"context"
 	"io"
+	"net"
 	"net/http"
+	"os"
+	"path/filepath"
 
 	"github.com/scylladb/go-set/strset"
 )
@@ -15,18 +18,24 @@ type (
 		Getwd() (string, error)
 		Open(string) (io.ReadCloser, error)
 		Write(string, []byte) error
+		Walk(root string, fn filepath.WalkFunc) error
+		Stat(string) (os.FileInfo, error)
 	}
 
 	// Logic represents application logic.
 	Logic interface {
-		Check(stdin io.Reader, cfgPath string) error
+		Check(stdin io.Reader, paths []string) ([]Result, error)
 		IsIgnoredURL(uri string) bool
-		CheckURLs(urls map[string]*strset.Set) error
-		CheckURLWithMethod(ctx context.Context, u, method string) error
-		CheckURL(ctx context.Context, u string) error
-		ExtractURLsFromFiles(files *strset.Set) (map[string]*strset.Set, error)
-		ExtractURLsFromFile(ctx context.Context, p string) (*strset.Set, error)
+		CheckURLs(urls map[string][]Location) ([]Result, error)
+		CheckURLWithMethod(ctx context.Context, u, method string) Result
+		CheckURL(ctx context.Context, u string) Result
+		ExtractURLsFromFiles(files *strset.Set) (map[string][]Location, error)
+		ExtractURLsFromFile(ctx context.Context, p string) ([]Link, error)
+		ExtractURLsFromDiff(diff io.Reader) (map[string][]Location, error)
 		GetFiles(stdin io.Reader) (*strset.Set, error)
+		FindFiles(paths []string) (*strset.Set, error)
+		ListGitFiles(paths []string) (*strset.Set, error)
+		Fix(results []Result, dryRun bool, w io.Writer) error
 	}
 
 	// CfgReader reads and parses the configuration file.
@@ -36,6 +45,25 @@ type (
 		InitCfg(cfg Cfg) (Cfg, error)
 	}
 
+	// Git represents operation to git.
+	Git interface {
+		ListFiles(paths []string) ([]string, error)
+		Diff(base string, paths []string) ([]byte, error)
+	}
+
+	// Reporter outputs results of checking urls.
+	Reporter interface {
+		// Report is called whenever a url is checked.
+		Report(result Result) error
+		// Finish is called after all urls are checked.
+		Finish(results []Result) error
+	}
+
+	// Resolver abstracts *net.Resolver .
+	Resolver interface {
+		LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	}
+
 	// HTTPClient abstracts *http.Client .
 	HTTPClient interface {
 		Do(req *http.Request) (*http.Response, error)
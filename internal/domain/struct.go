@@ -1,13 +1,199 @@
 package domain
 
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"time"
+)
+
 type (
 	// Cfg represents configuration.
 	Cfg struct {
-		IgnoreURLs            []string `yaml:"ignore_urls"`
-		IgnoreHosts           []string `yaml:"ignore_hosts"`
+		IgnoreURLs  []string `yaml:"ignore_urls"`
+		IgnoreHosts []string `yaml:"ignore_hosts"`
+		// DefaultIgnoreHosts adds the default hosts such as "example.com" to IgnoreHosts. If it is nil, it is regarded as true.
+		DefaultIgnoreHosts *bool `yaml:"default_ignore_hosts"`
+		// IgnoreURLPatterns are regular expressions of urls which aren't checked.
+		IgnoreURLPatterns     []string `yaml:"ignore_url_patterns"`
 		HTTPMethod            string   `yaml:"http_method"`
 		MaxRequestCount       int      `yaml:"max_request_count"`
 		MaxFailedRequestCount int      `yaml:"max_failed_request_count"`
 		HTTPRequestTimeout    int      `yaml:"http_request_timeout"`
+		Include               []string `yaml:"include"`
+		Exclude               []string `yaml:"exclude"`
+		FileSource            string   `yaml:"file_source"`
+		MaxFileSize           int64    `yaml:"max_file_size"`
+		MarkdownSkipCode      bool     `yaml:"markdown_skip_code"`
+		SkipLocalLinks        bool     `yaml:"skip_local_links"`
+		DocsRoot              string   `yaml:"docs_root"`
+		CheckAnchors          bool     `yaml:"check_anchors"`
+		// RetryCount is the max number of retries of a request which fails transiently.
+		RetryCount int `yaml:"retry_count"`
+		// RetryBackoff is the delay before the first retry, which is doubled per retry.
+		RetryBackoff time.Duration `yaml:"retry_backoff"`
+		// RetryMaxDelay is the max delay before a retry.
+		RetryMaxDelay time.Duration `yaml:"retry_max_delay"`
+		// RetryAfterMaxWait is the max total wait for the Retry-After header per url.
+		// If it is negative, the Retry-After header is ignored.
+		RetryAfterMaxWait time.Duration `yaml:"retry_after_max_wait"`
+		// PerHostMaxRequestCount is the max number of parallel requests per host. If it is 0, the number isn't limited.
+		PerHostMaxRequestCount int `yaml:"per_host_max_request_count"`
+		// PerHostRequestsPerSecond is the max request rate per host. If it is 0, the rate isn't limited.
+		PerHostRequestsPerSecond float64 `yaml:"per_host_requests_per_second"`
+		// Hosts overrides the settings per host. The key is the host such as "github.com".
+		Hosts map[string]HostCfg `yaml:"hosts"`
+		// AcceptedStatusCodes are status codes which aren't regarded as dead such as "200-299,401,403".
+		AcceptedStatusCodes string `yaml:"accepted_status_codes"`
+		// AcceptedStatusCodeRules override AcceptedStatusCodes per host or url.
+		AcceptedStatusCodeRules []StatusCodeRule `yaml:"accepted_status_code_rules"`
+		// Redirects is the policy of permanent redirects, which is "follow", "warn" or "error".
+		Redirects string `yaml:"redirects"`
+		// MaxRedirects is the max number of redirects which are followed.
+		MaxRedirects int `yaml:"max_redirects"`
+		// CacheFile is the path of the file which caches results of urls.
+		CacheFile string `yaml:"cache_file"`
+		// CacheTTL is the period while the cached result of a live url is used.
+		CacheTTL time.Duration `yaml:"cache_ttl"`
+		// CacheFailureTTL is the period while the cached result of a dead url is used.
+		CacheFailureTTL time.Duration `yaml:"cache_failure_ttl"`
+		// NoCache disables the cache. It is also set by the --no-cache option.
+		NoCache bool `yaml:"no_cache"`
+		// Variables are values of placeholders "${VAR}" in urls.
+		Variables map[string]string `yaml:"variables"`
+		// ExpandEnv expands placeholders "${VAR}" in urls with environment variables.
+		ExpandEnv bool `yaml:"expand_env"`
+		// IgnoreCIDRs are IP ranges such as "10.0.0.0/8". Urls whose host is or resolves to an address in them aren't checked.
+		IgnoreCIDRs []string `yaml:"ignore_cidrs"`
+		// IgnorePrivateIPs ignores private, loopback and link-local addresses in addition to IgnoreCIDRs.
+		IgnorePrivateIPs bool `yaml:"ignore_private_ips"`
+		// DiffBase is set by the --diff option.
+		DiffBase string `yaml:"-"`
+
+		// IncludePatterns and ExcludePatterns are compiled from Include and Exclude by CfgReader.InitCfg .
+		IncludePatterns []*regexp.Regexp `yaml:"-"`
+		ExcludePatterns []*regexp.Regexp `yaml:"-"`
+		// AcceptedStatusCodeRanges is parsed from AcceptedStatusCodes by CfgReader.InitCfg .
+		AcceptedStatusCodeRanges []StatusCodeRange `yaml:"-"`
+		// IgnoreURLRegexps are compiled from wildcard entries of IgnoreURLs and IgnoreURLPatterns by CfgReader.InitCfg .
+		IgnoreURLRegexps []*regexp.Regexp `yaml:"-"`
+		// IgnoreHostRegexps are compiled from IgnoreHosts and DefaultIgnoreHosts by CfgReader.InitCfg .
+		IgnoreHostRegexps []*regexp.Regexp `yaml:"-"`
+		// IgnoreIPNets are parsed from IgnoreCIDRs and IgnorePrivateIPs by CfgReader.InitCfg .
+		IgnoreIPNets []*net.IPNet `yaml:"-"`
+	}
+
+	// StatusCodeRule is a rule of accepted status codes per host or url.
+	StatusCodeRule struct {
+		Host string `yaml:"host"`
+		// URL is a regular expression of urls.
+		URL         string `yaml:"url"`
+		StatusCodes string `yaml:"status_codes"`
+
+		// URLPattern and StatusCodeRanges are compiled from URL and StatusCodes by CfgReader.InitCfg .
+		URLPattern       *regexp.Regexp    `yaml:"-"`
+		StatusCodeRanges []StatusCodeRange `yaml:"-"`
+	}
+
+	// StatusCodeRange is a range of HTTP status codes.
+	StatusCodeRange struct {
+		Min int
+		Max int
+	}
+
+	// HostCfg is the setting per host.
+	HostCfg struct {
+		MaxRequestCount   int     `yaml:"max_request_count"`
+		RequestsPerSecond float64 `yaml:"requests_per_second"`
+	}
+
+	// Result is a result of checking a url.
+	Result struct {
+		URL string
+		// Locations are positions of the url in files.
+		Locations []Location
+		// Method is the HTTP method of the last request.
+		Method     string
+		StatusCode int
+		// ErrorKind is the category of the failure. If the url isn't dead, ErrorKind is empty.
+		ErrorKind string
+		Error     string
+		Duration  time.Duration
+		// Redirects are redirects which are followed in order.
+		Redirects []Redirect
+		// Attempts is the number of requests by the method including retries.
+		Attempts int
+		// SuggestedURL is the url which the url is permanently redirected to.
+		SuggestedURL string
+		// Warning is the warning message such as a permanent redirect, which doesn't make the url dead.
+		Warning string
+		// Cached is true if the result is got from the cache instead of requests.
+		Cached bool
+	}
+
+	// Link is a url in a file.
+	Link struct {
+		URL      string
+		Location Location
+	}
+
+	// Location is a position in a file.
+	Location struct {
+		Path string
+		// Line is a line number starting at 1.
+		Line int
+		// Column is a column number in characters starting at 1.
+		Column int
+		// Context is the element and attribute which have the url such as "a[href]" in a HTML file.
+		Context string
+	}
+
+	// Redirect represents a HTTP redirect.
+	Redirect struct {
+		StatusCode int
+		// URL is the location of the redirect.
+		URL string
 	}
 )
+
+// Failed returns true if the url is dead.
+func (result Result) Failed() bool {
+	return result.ErrorKind != ""
+}
+
+// Files returns paths of files which include the url without duplication.
+func (result Result) Files() []string {
+	files := []string{}
+	found := map[string]struct{}{}
+	for _, loc := range result.Locations {
+		if _, ok := found[loc.Path]; ok {
+			continue
+		}
+		found[loc.Path] = struct{}{}
+		files = append(files, loc.Path)
+	}
+	return files
+}
+
+// String returns a string like "README.md:3:5" .
+func (loc Location) String() string {
+	if loc.Line == 0 {
+		return loc.Path
+	}
+	return fmt.Sprintf("%s:%d:%d", loc.Path, loc.Line, loc.Column)
+}
+
+// SortLocations sorts locations by the file path, line and column.
+func SortLocations(locs []Location) {
+	sort.Slice(locs, func(i, j int) bool {
+		a, b := locs[i], locs[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"io"
 	"net/http"
 	"os"
 	"time"
@@ -10,38 +11,92 @@ import (
 	"github.com/suzuki-shunsuke/go-cliutil"
 	"github.com/urfave/cli/v2"
 
+	"github.com/suzuki-shunsuke/durl/internal/domain"
 	"github.com/suzuki-shunsuke/durl/internal/infra"
 	"github.com/suzuki-shunsuke/durl/internal/usecase"
 )
 
+// checkFlags are flags of the sub commands which check urls.
+var checkFlags = []cli.Flag{ //nolint:gochecknoglobals
+	&cli.StringFlag{
+		Name:  "config, c",
+		Usage: "configuration file path",
+		Value: "",
+	},
+	&cli.BoolFlag{
+		Name:  "git",
+		Usage: "check files which are tracked by git or aren't ignored by .gitignore",
+	},
+	&cli.StringFlag{
+		Name:  "format",
+		Usage: "output format (text, json, ndjson, sarif, junit)",
+		Value: "text",
+	},
+	&cli.StringFlag{
+		Name:  "diff",
+		Usage: "check only urls in lines which are added or modified since the git ref",
+	},
+	&cli.BoolFlag{
+		Name:  "no-cache",
+		Usage: "check all urls without reading and writing the cache",
+	},
+}
+
 // checkCommand is the sub command "check".
 var checkCommand = cli.Command{ //nolint:gochecknoglobals
-	Name:   "check",
-	Usage:  "check files",
-	Action: check,
-	Flags: []cli.Flag{
-		&cli.StringFlag{
-			Name:  "config, c",
-			Usage: "configuration file path",
-			Value: "",
-		},
-	},
+	Name:      "check",
+	Usage:     "check files",
+	ArgsUsage: "[file or directory ...]",
+	Action:    check,
+	Flags:     checkFlags,
 }
 
 func check(c *cli.Context) error {
+	logic, reporter, stdin, err := setup(c, nil)
+	if err != nil {
+		return cliutil.ConvErrToExitError(err)
+	}
+	results, checkErr := logic.Check(stdin, c.Args().Slice())
+	if err := reporter.Finish(results); err != nil {
+		return cliutil.ConvErrToExitError(err)
+	}
+	return cliutil.ConvErrToExitError(checkErr)
+}
+
+// setup reads the configuration and returns the logic, the reporter and stdin to check urls.
+// If editCfg isn't nil, it is called to overwrite the configuration for the sub command.
+// If stdin shouldn't be read, nil is returned as stdin.
+func setup(c *cli.Context, editCfg func(cfg *domain.Cfg)) (domain.Logic, domain.Reporter, io.Reader, error) {
 	cfgPath := c.String("config")
 	fsys := infra.Fsys{}
 	cfgReader := usecase.NewCfgReader(fsys)
 	cfg, err := cfgReader.ReadCfg(cfgPath)
 	if err != nil {
-		return cliutil.ConvErrToExitError(err)
+		return nil, nil, nil, err
+	}
+	if c.Bool("git") {
+		cfg.FileSource = domain.FileSourceGit
+	}
+	cfg.DiffBase = c.String("diff")
+	if c.Bool("no-cache") {
+		cfg.NoCache = true
+	}
+	if editCfg != nil {
+		editCfg(&cfg)
+	}
+	reporter, err := usecase.NewReporter(c.String("format"), os.Stdout, os.Stderr)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 	logic := usecase.NewLogic(
 		cfg, fsys, &http.Client{
-			Timeout: time.Duration(cfg.HTTPRequestTimeout) * time.Second,
-		})
-	if terminal.IsTerminal(0) {
-		return cliutil.ConvErrToExitError(logic.Check(nil, cfgPath))
+			Timeout:       time.Duration(cfg.HTTPRequestTimeout) * time.Second,
+			CheckRedirect: usecase.NewCheckRedirect(cfg.MaxRedirects),
+		}, infra.Git{}, reporter)
+	// if file or directory paths are given as arguments, walk them instead of reading stdin
+	var stdin io.Reader
+	if c.Args().Len() == 0 && cfg.FileSource != domain.FileSourceGit && cfg.DiffBase == "" && !terminal.IsTerminal(0) {
+		stdin = os.Stdin
 	}
-	return cliutil.ConvErrToExitError(logic.Check(os.Stdin, cfgPath))
+	return logic, reporter, stdin, nil
 }
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"io"
+	"os"
+
+	"github.com/suzuki-shunsuke/go-cliutil"
+	"github.com/urfave/cli/v2"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// fixCommand is the sub command "fix".
+var fixCommand = cli.Command{ //nolint:gochecknoglobals
+	Name:      "fix",
+	Usage:     "check files and rewrite permanently redirected urls to the final urls",
+	ArgsUsage: "[file or directory ...]",
+	Action:    fix,
+	Flags: append([]cli.Flag{
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "output the unified diff instead of rewriting files",
+		},
+	}, checkFlags...),
+}
+
+func fix(c *cli.Context) error {
+	logic, reporter, stdin, err := setup(c, func(cfg *domain.Cfg) {
+		// all urls are checked even if dead urls are found, so that all permanently redirected urls are fixed
+		cfg.MaxFailedRequestCount = -1
+	})
+	if err != nil {
+		return cliutil.ConvErrToExitError(err)
+	}
+	results, checkErr := logic.Check(stdin, c.Args().Slice())
+	if err := reporter.Finish(results); err != nil {
+		return cliutil.ConvErrToExitError(err)
+	}
+	// the formats other than text are output to stdout, so the output of fix is output to stderr not to break them
+	var w io.Writer = os.Stdout
+	if format := c.String("format"); format != domain.FormatText && format != "" {
+		w = os.Stderr
+	}
+	// permanently redirected urls are fixed even if the other urls are dead
+	if err := logic.Fix(results, c.Bool("dry-run"), w); err != nil {
+		return cliutil.ConvErrToExitError(err)
+	}
+	return cliutil.ConvErrToExitError(checkErr)
+}
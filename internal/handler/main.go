@@ -18,6 +18,7 @@ func Main() {
 	app.Commands = []*cli.Command{
 		&initCommand,
 		&checkCommand,
+		&fixCommand,
 	}
 	_ = app.Run(os.Args)
 }
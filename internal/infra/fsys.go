@@ -4,6 +4,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 )
 
 type (
@@ -22,7 +23,7 @@ func (fsys Fsys) Exist(dst string) bool {
 	return err == nil
 }
 
-// Write writes data to a file.
+// Write writes data to a file. If the file exists, it is truncated and the permission is kept.
 func (fsys Fsys) Write(dst string, data []byte) error {
 	return ioutil.WriteFile(dst, data, 0o644) //nolint:gosec
 }
@@ -31,3 +32,13 @@ func (fsys Fsys) Write(dst string, data []byte) error {
 func (fsys Fsys) Getwd() (string, error) {
 	return os.Getwd()
 }
+
+// Walk walks the file tree rooted at root.
+func (fsys Fsys) Walk(root string, fn filepath.WalkFunc) error {
+	return filepath.Walk(root, fn)
+}
+
+// Stat returns a FileInfo describing the file.
+func (fsys Fsys) Stat(p string) (os.FileInfo, error) {
+	return os.Stat(p)
+}
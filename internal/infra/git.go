@@ -0,0 +1,58 @@
+package infra
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+type (
+	// Git represents operation to git.
+	Git struct{}
+)
+
+// ListFiles returns paths of files which are tracked by git and untracked files which aren't ignored by .gitignore .
+func (g Git) ListFiles(paths []string) ([]string, error) {
+	args := append([]string{
+		"ls-files", "-z", "--cached", "--others", "--exclude-standard", "--",
+	}, paths...)
+	out, err := g.run(args...)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{}
+	for _, f := range strings.Split(string(out), "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// Diff returns the difference between the working tree and the merge base of the base commit and HEAD,
+// so that changes of the base branch after the current branch is created aren't included.
+// File paths are relative to the current directory and they have the prefixes "a/" and "b/" regardless of the git config.
+func (g Git) Diff(base string, paths []string) ([]byte, error) {
+	out, err := g.run("merge-base", base, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	mergeBase := strings.TrimSpace(string(out))
+	args := append([]string{
+		"-c", "core.quotePath=false", "diff", "--no-color", "--no-ext-diff", "--unified=0",
+		"--relative", "--src-prefix=a/", "--dst-prefix=b/", mergeBase, "--",
+	}, paths...)
+	return g.run(args...)
+}
+
+func (g Git) run(args ...string) ([]byte, error) {
+	stderr := &bytes.Buffer{}
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to run git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
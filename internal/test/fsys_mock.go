@@ -6,6 +6,8 @@ package test
 
 import (
 	"io"
+	"os"
+	"path/filepath"
 	testing "testing"
 
 	gomic "github.com/suzuki-shunsuke/gomic/gomic"
@@ -22,6 +24,8 @@ type (
 			Getwd func() (string, error)
 			Open  func(p0 string) (io.ReadCloser, error)
 			Write func(p0 string, p1 []byte) error
+			Walk  func(root string, fn filepath.WalkFunc) error
+			Stat  func(p0 string) (os.FileInfo, error)
 		}
 	}
 )
@@ -177,3 +181,76 @@ func (mock Fsys) fakeZeroWrite(p0 string, p1 []byte) error {
 	)
 	return r0
 }
+
+// Walk is a mock method.
+func (mock Fsys) Walk(root string, fn filepath.WalkFunc) error {
+	methodName := "Walk" // nolint: goconst
+	if mock.impl.Walk != nil {
+		return mock.impl.Walk(root, fn)
+	}
+	if mock.callbackNotImplemented != nil {
+		mock.callbackNotImplemented(mock.t, mock.name, methodName)
+	} else {
+		gomic.DefaultCallbackNotImplemented(mock.t, mock.name, methodName)
+	}
+	return mock.fakeZeroWalk(root, fn)
+}
+
+// SetFuncWalk sets a method and returns the mock.
+func (mock *Fsys) SetFuncWalk(impl func(root string, fn filepath.WalkFunc) error) *Fsys {
+	mock.impl.Walk = impl
+	return mock
+}
+
+// SetReturnWalk sets a fake method.
+func (mock *Fsys) SetReturnWalk(r0 error) *Fsys {
+	mock.impl.Walk = func(string, filepath.WalkFunc) error {
+		return r0
+	}
+	return mock
+}
+
+// fakeZeroWalk is a fake method which returns zero values.
+func (mock Fsys) fakeZeroWalk(root string, fn filepath.WalkFunc) error {
+	var (
+		r0 error
+	)
+	return r0
+}
+
+// Stat is a mock method.
+func (mock Fsys) Stat(p0 string) (os.FileInfo, error) {
+	methodName := "Stat" // nolint: goconst
+	if mock.impl.Stat != nil {
+		return mock.impl.Stat(p0)
+	}
+	if mock.callbackNotImplemented != nil {
+		mock.callbackNotImplemented(mock.t, mock.name, methodName)
+	} else {
+		gomic.DefaultCallbackNotImplemented(mock.t, mock.name, methodName)
+	}
+	return mock.fakeZeroStat(p0)
+}
+
+// SetFuncStat sets a method and returns the mock.
+func (mock *Fsys) SetFuncStat(impl func(p0 string) (os.FileInfo, error)) *Fsys {
+	mock.impl.Stat = impl
+	return mock
+}
+
+// SetReturnStat sets a fake method.
+func (mock *Fsys) SetReturnStat(r0 os.FileInfo, r1 error) *Fsys {
+	mock.impl.Stat = func(string) (os.FileInfo, error) {
+		return r0, r1
+	}
+	return mock
+}
+
+// fakeZeroStat is a fake method which returns zero values.
+func (mock Fsys) fakeZeroStat(p0 string) (os.FileInfo, error) {
+	var (
+		r0 os.FileInfo
+		r1 error
+	)
+	return r0, r1
+}
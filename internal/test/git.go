@@ -0,0 +1,104 @@
+package test
+
+// Don't edit this file.
+// This file is generated by gomic 0.5.2.
+// https://github.com/suzuki-shunsuke/gomic
+
+import (
+	testing "testing"
+
+	gomic "github.com/suzuki-shunsuke/gomic/gomic"
+)
+
+type (
+	// Git is a mock.
+	Git struct {
+		t                      *testing.T
+		name                   string
+		callbackNotImplemented gomic.CallbackNotImplemented
+		impl                   struct {
+			ListFiles func(paths []string) ([]string, error)
+			Diff      func(base string, paths []string) ([]byte, error)
+		}
+	}
+)
+
+// NewGit returns Git .
+func NewGit(t *testing.T, cb gomic.CallbackNotImplemented) *Git {
+	return &Git{
+		t: t, name: "Git", callbackNotImplemented: cb}
+}
+
+// ListFiles is a mock method.
+func (mock Git) ListFiles(paths []string) ([]string, error) {
+	methodName := "ListFiles" // nolint: goconst
+	if mock.impl.ListFiles != nil {
+		return mock.impl.ListFiles(paths)
+	}
+	if mock.callbackNotImplemented != nil {
+		mock.callbackNotImplemented(mock.t, mock.name, methodName)
+	} else {
+		gomic.DefaultCallbackNotImplemented(mock.t, mock.name, methodName)
+	}
+	return mock.fakeZeroListFiles(paths)
+}
+
+// SetFuncListFiles sets a method and returns the mock.
+func (mock *Git) SetFuncListFiles(impl func(paths []string) ([]string, error)) *Git {
+	mock.impl.ListFiles = impl
+	return mock
+}
+
+// SetReturnListFiles sets a fake method.
+func (mock *Git) SetReturnListFiles(r0 []string, r1 error) *Git {
+	mock.impl.ListFiles = func([]string) ([]string, error) {
+		return r0, r1
+	}
+	return mock
+}
+
+// fakeZeroListFiles is a fake method which returns zero values.
+func (mock Git) fakeZeroListFiles(paths []string) ([]string, error) {
+	var (
+		r0 []string
+		r1 error
+	)
+	return r0, r1
+}
+
+// Diff is a mock method.
+func (mock Git) Diff(base string, paths []string) ([]byte, error) {
+	methodName := "Diff" // nolint: goconst
+	if mock.impl.Diff != nil {
+		return mock.impl.Diff(base, paths)
+	}
+	if mock.callbackNotImplemented != nil {
+		mock.callbackNotImplemented(mock.t, mock.name, methodName)
+	} else {
+		gomic.DefaultCallbackNotImplemented(mock.t, mock.name, methodName)
+	}
+	return mock.fakeZeroDiff(base, paths)
+}
+
+// SetFuncDiff sets a method and returns the mock.
+func (mock *Git) SetFuncDiff(impl func(base string, paths []string) ([]byte, error)) *Git {
+	mock.impl.Diff = impl
+	return mock
+}
+
+// SetReturnDiff sets a fake method.
+func (mock *Git) SetReturnDiff(r0 []byte, r1 error) *Git {
+	mock.impl.Diff = func(string, []string) ([]byte, error) {
+		return r0, r1
+	}
+	return mock
+}
+
+// fakeZeroDiff is a fake method which returns zero values.
+func (mock Git) fakeZeroDiff(base string, paths []string) ([]byte, error) {
+	var (
+		r0 []byte
+		r1 error
+	)
+	return r0, r1
+}
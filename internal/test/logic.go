@@ -10,6 +10,7 @@ import (
 	testing "testing"
 
 	"github.com/scylladb/go-set/strset"
+	domain "github.com/suzuki-shunsuke/durl/internal/domain"
 	gomic "github.com/suzuki-shunsuke/gomic/gomic"
 )
 
@@ -20,14 +21,18 @@ type (
 		name                   string
 		callbackNotImplemented gomic.CallbackNotImplemented
 		impl                   struct {
-			Check                func(stdin io.Reader, cfgPath string) error
+			Check                func(stdin io.Reader, paths []string) ([]domain.Result, error)
 			IsIgnoredURL         func(uri string) bool
-			CheckURLs            func(urls map[string]*strset.Set) error
-			CheckURLWithMethod   func(ctx context.Context, u, method string) error
-			CheckURL             func(ctx context.Context, u string) error
-			ExtractURLsFromFiles func(files *strset.Set) (map[string]*strset.Set, error)
-			ExtractURLsFromFile  func(ctx context.Context, p string) (*strset.Set, error)
+			CheckURLs            func(urls map[string][]domain.Location) ([]domain.Result, error)
+			CheckURLWithMethod   func(ctx context.Context, u, method string) domain.Result
+			CheckURL             func(ctx context.Context, u string) domain.Result
+			ExtractURLsFromFiles func(files *strset.Set) (map[string][]domain.Location, error)
+			ExtractURLsFromFile  func(ctx context.Context, p string) ([]domain.Link, error)
+			ExtractURLsFromDiff  func(diff io.Reader) (map[string][]domain.Location, error)
 			GetFiles             func(stdin io.Reader) (*strset.Set, error)
+			FindFiles            func(paths []string) (*strset.Set, error)
+			ListGitFiles         func(paths []string) (*strset.Set, error)
+			Fix                  func(results []domain.Result, dryRun bool, w io.Writer) error
 		}
 	}
 )
@@ -39,39 +44,40 @@ func NewLogic(t *testing.T, cb gomic.CallbackNotImplemented) *Logic {
 }
 
 // Check is a mock method.
-func (mock Logic) Check(stdin io.Reader, cfgPath string) error {
+func (mock Logic) Check(stdin io.Reader, paths []string) ([]domain.Result, error) {
 	methodName := "Check" // nolint: goconst
 	if mock.impl.Check != nil {
-		return mock.impl.Check(stdin, cfgPath)
+		return mock.impl.Check(stdin, paths)
 	}
 	if mock.callbackNotImplemented != nil {
 		mock.callbackNotImplemented(mock.t, mock.name, methodName)
 	} else {
 		gomic.DefaultCallbackNotImplemented(mock.t, mock.name, methodName)
 	}
-	return mock.fakeZeroCheck(stdin, cfgPath)
+	return mock.fakeZeroCheck(stdin, paths)
 }
 
 // SetFuncCheck sets a method and returns the mock.
-func (mock *Logic) SetFuncCheck(impl func(stdin io.Reader, cfgPath string) error) *Logic {
+func (mock *Logic) SetFuncCheck(impl func(stdin io.Reader, paths []string) ([]domain.Result, error)) *Logic {
 	mock.impl.Check = impl
 	return mock
 }
 
 // SetReturnCheck sets a fake method.
-func (mock *Logic) SetReturnCheck(r0 error) *Logic {
-	mock.impl.Check = func(io.Reader, string) error {
-		return r0
+func (mock *Logic) SetReturnCheck(r0 []domain.Result, r1 error) *Logic {
+	mock.impl.Check = func(io.Reader, []string) ([]domain.Result, error) {
+		return r0, r1
 	}
 	return mock
 }
 
 // fakeZeroCheck is a fake method which returns zero values.
-func (mock Logic) fakeZeroCheck(stdin io.Reader, cfgPath string) error {
+func (mock Logic) fakeZeroCheck(stdin io.Reader, paths []string) ([]domain.Result, error) {
 	var (
-		r0 error
+		r0 []domain.Result
+		r1 error
 	)
-	return r0
+	return r0, r1
 }
 
 // IsIgnoredURL is a mock method.
@@ -111,7 +117,7 @@ func (mock Logic) fakeZeroIsIgnoredURL(uri string) bool {
 }
 
 // CheckURLs is a mock method.
-func (mock Logic) CheckURLs(urls map[string]*strset.Set) error {
+func (mock Logic) CheckURLs(urls map[string][]domain.Location) ([]domain.Result, error) {
 	methodName := "CheckURLs" // nolint: goconst
 	if mock.impl.CheckURLs != nil {
 		return mock.impl.CheckURLs(urls)
@@ -125,29 +131,30 @@ func (mock Logic) CheckURLs(urls map[string]*strset.Set) error {
 }
 
 // SetFuncCheckURLs sets a method and returns the mock.
-func (mock *Logic) SetFuncCheckURLs(impl func(urls map[string]*strset.Set) error) *Logic {
+func (mock *Logic) SetFuncCheckURLs(impl func(urls map[string][]domain.Location) ([]domain.Result, error)) *Logic {
 	mock.impl.CheckURLs = impl
 	return mock
 }
 
 // SetReturnCheckURLs sets a fake method.
-func (mock *Logic) SetReturnCheckURLs(r0 error) *Logic {
-	mock.impl.CheckURLs = func(map[string]*strset.Set) error {
-		return r0
+func (mock *Logic) SetReturnCheckURLs(r0 []domain.Result, r1 error) *Logic {
+	mock.impl.CheckURLs = func(map[string][]domain.Location) ([]domain.Result, error) {
+		return r0, r1
 	}
 	return mock
 }
 
 // fakeZeroCheckURLs is a fake method which returns zero values.
-func (mock Logic) fakeZeroCheckURLs(urls map[string]*strset.Set) error {
+func (mock Logic) fakeZeroCheckURLs(urls map[string][]domain.Location) ([]domain.Result, error) {
 	var (
-		r0 error
+		r0 []domain.Result
+		r1 error
 	)
-	return r0
+	return r0, r1
 }
 
 // CheckURLWithMethod is a mock method.
-func (mock Logic) CheckURLWithMethod(ctx context.Context, u, method string) error {
+func (mock Logic) CheckURLWithMethod(ctx context.Context, u, method string) domain.Result {
 	methodName := "CheckURLWithMethod" // nolint: goconst
 	if mock.impl.CheckURLWithMethod != nil {
 		return mock.impl.CheckURLWithMethod(ctx, u, method)
@@ -161,29 +168,29 @@ func (mock Logic) CheckURLWithMethod(ctx context.Context, u, method string) erro
 }
 
 // SetFuncCheckURLWithMethod sets a method and returns the mock.
-func (mock *Logic) SetFuncCheckURLWithMethod(impl func(ctx context.Context, u, method string) error) *Logic {
+func (mock *Logic) SetFuncCheckURLWithMethod(impl func(ctx context.Context, u, method string) domain.Result) *Logic {
 	mock.impl.CheckURLWithMethod = impl
 	return mock
 }
 
 // SetReturnCheckURLWithMethod sets a fake method.
-func (mock *Logic) SetReturnCheckURLWithMethod(r0 error) *Logic {
-	mock.impl.CheckURLWithMethod = func(context.Context, string, string) error {
+func (mock *Logic) SetReturnCheckURLWithMethod(r0 domain.Result) *Logic {
+	mock.impl.CheckURLWithMethod = func(context.Context, string, string) domain.Result {
 		return r0
 	}
 	return mock
 }
 
 // fakeZeroCheckURLWithMethod is a fake method which returns zero values.
-func (mock Logic) fakeZeroCheckURLWithMethod(ctx context.Context, u, method string) error {
+func (mock Logic) fakeZeroCheckURLWithMethod(ctx context.Context, u, method string) domain.Result {
 	var (
-		r0 error
+		r0 domain.Result
 	)
 	return r0
 }
 
 // CheckURL is a mock method.
-func (mock Logic) CheckURL(ctx context.Context, u string) error {
+func (mock Logic) CheckURL(ctx context.Context, u string) domain.Result {
 	methodName := "CheckURL" // nolint: goconst
 	if mock.impl.CheckURL != nil {
 		return mock.impl.CheckURL(ctx, u)
@@ -197,29 +204,29 @@ func (mock Logic) CheckURL(ctx context.Context, u string) error {
 }
 
 // SetFuncCheckURL sets a method and returns the mock.
-func (mock *Logic) SetFuncCheckURL(impl func(ctx context.Context, u string) error) *Logic {
+func (mock *Logic) SetFuncCheckURL(impl func(ctx context.Context, u string) domain.Result) *Logic {
 	mock.impl.CheckURL = impl
 	return mock
 }
 
 // SetReturnCheckURL sets a fake method.
-func (mock *Logic) SetReturnCheckURL(r0 error) *Logic {
-	mock.impl.CheckURL = func(context.Context, string) error {
+func (mock *Logic) SetReturnCheckURL(r0 domain.Result) *Logic {
+	mock.impl.CheckURL = func(context.Context, string) domain.Result {
 		return r0
 	}
 	return mock
 }
 
 // fakeZeroCheckURL is a fake method which returns zero values.
-func (mock Logic) fakeZeroCheckURL(ctx context.Context, u string) error {
+func (mock Logic) fakeZeroCheckURL(ctx context.Context, u string) domain.Result {
 	var (
-		r0 error
+		r0 domain.Result
 	)
 	return r0
 }
 
 // ExtractURLsFromFiles is a mock method.
-func (mock Logic) ExtractURLsFromFiles(files *strset.Set) (map[string]*strset.Set, error) {
+func (mock Logic) ExtractURLsFromFiles(files *strset.Set) (map[string][]domain.Location, error) {
 	methodName := "ExtractURLsFromFiles" // nolint: goconst
 	if mock.impl.ExtractURLsFromFiles != nil {
 		return mock.impl.ExtractURLsFromFiles(files)
@@ -233,30 +240,30 @@ func (mock Logic) ExtractURLsFromFiles(files *strset.Set) (map[string]*strset.Se
 }
 
 // SetFuncExtractURLsFromFiles sets a method and returns the mock.
-func (mock *Logic) SetFuncExtractURLsFromFiles(impl func(files *strset.Set) (map[string]*strset.Set, error)) *Logic {
+func (mock *Logic) SetFuncExtractURLsFromFiles(impl func(files *strset.Set) (map[string][]domain.Location, error)) *Logic {
 	mock.impl.ExtractURLsFromFiles = impl
 	return mock
 }
 
 // SetReturnExtractURLsFromFiles sets a fake method.
-func (mock *Logic) SetReturnExtractURLsFromFiles(r0 map[string]*strset.Set, r1 error) *Logic {
-	mock.impl.ExtractURLsFromFiles = func(*strset.Set) (map[string]*strset.Set, error) {
+func (mock *Logic) SetReturnExtractURLsFromFiles(r0 map[string][]domain.Location, r1 error) *Logic {
+	mock.impl.ExtractURLsFromFiles = func(*strset.Set) (map[string][]domain.Location, error) {
 		return r0, r1
 	}
 	return mock
 }
 
 // fakeZeroExtractURLsFromFiles is a fake method which returns zero values.
-func (mock Logic) fakeZeroExtractURLsFromFiles(files *strset.Set) (map[string]*strset.Set, error) {
+func (mock Logic) fakeZeroExtractURLsFromFiles(files *strset.Set) (map[string][]domain.Location, error) {
 	var (
-		r0 map[string]*strset.Set
+		r0 map[string][]domain.Location
 		r1 error
 	)
 	return r0, r1
 }
 
 // ExtractURLsFromFile is a mock method.
-func (mock Logic) ExtractURLsFromFile(ctx context.Context, p string) (*strset.Set, error) {
+func (mock Logic) ExtractURLsFromFile(ctx context.Context, p string) ([]domain.Link, error) {
 	methodName := "ExtractURLsFromFile" // nolint: goconst
 	if mock.impl.ExtractURLsFromFile != nil {
 		return mock.impl.ExtractURLsFromFile(ctx, p)
@@ -270,23 +277,60 @@ func (mock Logic) ExtractURLsFromFile(ctx context.Context, p string) (*strset.Se
 }
 
 // SetFuncExtractURLsFromFile sets a method and returns the mock.
-func (mock *Logic) SetFuncExtractURLsFromFile(impl func(ctx context.Context, p string) (*strset.Set, error)) *Logic {
+func (mock *Logic) SetFuncExtractURLsFromFile(impl func(ctx context.Context, p string) ([]domain.Link, error)) *Logic {
 	mock.impl.ExtractURLsFromFile = impl
 	return mock
 }
 
 // SetReturnExtractURLsFromFile sets a fake method.
-func (mock *Logic) SetReturnExtractURLsFromFile(r0 *strset.Set, r1 error) *Logic {
-	mock.impl.ExtractURLsFromFile = func(context.Context, string) (*strset.Set, error) {
+func (mock *Logic) SetReturnExtractURLsFromFile(r0 []domain.Link, r1 error) *Logic {
+	mock.impl.ExtractURLsFromFile = func(context.Context, string) ([]domain.Link, error) {
 		return r0, r1
 	}
 	return mock
 }
 
 // fakeZeroExtractURLsFromFile is a fake method which returns zero values.
-func (mock Logic) fakeZeroExtractURLsFromFile(ctx context.Context, p string) (*strset.Set, error) {
+func (mock Logic) fakeZeroExtractURLsFromFile(ctx context.Context, p string) ([]domain.Link, error) {
 	var (
-		r0 *strset.Set
+		r0 []domain.Link
+		r1 error
+	)
+	return r0, r1
+}
+
+// ExtractURLsFromDiff is a mock method.
+func (mock Logic) ExtractURLsFromDiff(diff io.Reader) (map[string][]domain.Location, error) {
+	methodName := "ExtractURLsFromDiff" // nolint: goconst
+	if mock.impl.ExtractURLsFromDiff != nil {
+		return mock.impl.ExtractURLsFromDiff(diff)
+	}
+	if mock.callbackNotImplemented != nil {
+		mock.callbackNotImplemented(mock.t, mock.name, methodName)
+	} else {
+		gomic.DefaultCallbackNotImplemented(mock.t, mock.name, methodName)
+	}
+	return mock.fakeZeroExtractURLsFromDiff(diff)
+}
+
+// SetFuncExtractURLsFromDiff sets a method and returns the mock.
+func (mock *Logic) SetFuncExtractURLsFromDiff(impl func(diff io.Reader) (map[string][]domain.Location, error)) *Logic {
+	mock.impl.ExtractURLsFromDiff = impl
+	return mock
+}
+
+// SetReturnExtractURLsFromDiff sets a fake method.
+func (mock *Logic) SetReturnExtractURLsFromDiff(r0 map[string][]domain.Location, r1 error) *Logic {
+	mock.impl.ExtractURLsFromDiff = func(io.Reader) (map[string][]domain.Location, error) {
+		return r0, r1
+	}
+	return mock
+}
+
+// fakeZeroExtractURLsFromDiff is a fake method which returns zero values.
+func (mock Logic) fakeZeroExtractURLsFromDiff(diff io.Reader) (map[string][]domain.Location, error) {
+	var (
+		r0 map[string][]domain.Location
 		r1 error
 	)
 	return r0, r1
@@ -328,3 +372,113 @@ func (mock Logic) fakeZeroGetFiles(stdin io.Reader) (*strset.Set, error) {
 	)
 	return r0, r1
 }
+
+// FindFiles is a mock method.
+func (mock Logic) FindFiles(paths []string) (*strset.Set, error) {
+	methodName := "FindFiles" // nolint: goconst
+	if mock.impl.FindFiles != nil {
+		return mock.impl.FindFiles(paths)
+	}
+	if mock.callbackNotImplemented != nil {
+		mock.callbackNotImplemented(mock.t, mock.name, methodName)
+	} else {
+		gomic.DefaultCallbackNotImplemented(mock.t, mock.name, methodName)
+	}
+	return mock.fakeZeroFindFiles(paths)
+}
+
+// SetFuncFindFiles sets a method and returns the mock.
+func (mock *Logic) SetFuncFindFiles(impl func(paths []string) (*strset.Set, error)) *Logic {
+	mock.impl.FindFiles = impl
+	return mock
+}
+
+// SetReturnFindFiles sets a fake method.
+func (mock *Logic) SetReturnFindFiles(r0 *strset.Set, r1 error) *Logic {
+	mock.impl.FindFiles = func([]string) (*strset.Set, error) {
+		return r0, r1
+	}
+	return mock
+}
+
+// fakeZeroFindFiles is a fake method which returns zero values.
+func (mock Logic) fakeZeroFindFiles(paths []string) (*strset.Set, error) {
+	var (
+		r0 *strset.Set
+		r1 error
+	)
+	return r0, r1
+}
+
+// ListGitFiles is a mock method.
+func (mock Logic) ListGitFiles(paths []string) (*strset.Set, error) {
+	methodName := "ListGitFiles" // nolint: goconst
+	if mock.impl.ListGitFiles != nil {
+		return mock.impl.ListGitFiles(paths)
+	}
+	if mock.callbackNotImplemented != nil {
+		mock.callbackNotImplemented(mock.t, mock.name, methodName)
+	} else {
+		gomic.DefaultCallbackNotImplemented(mock.t, mock.name, methodName)
+	}
+	return mock.fakeZeroListGitFiles(paths)
+}
+
+// SetFuncListGitFiles sets a method and returns the mock.
+func (mock *Logic) SetFuncListGitFiles(impl func(paths []string) (*strset.Set, error)) *Logic {
+	mock.impl.ListGitFiles = impl
+	return mock
+}
+
+// SetReturnListGitFiles sets a fake method.
+func (mock *Logic) SetReturnListGitFiles(r0 *strset.Set, r1 error) *Logic {
+	mock.impl.ListGitFiles = func([]string) (*strset.Set, error) {
+		return r0, r1
+	}
+	return mock
+}
+
+// fakeZeroListGitFiles is a fake method which returns zero values.
+func (mock Logic) fakeZeroListGitFiles(paths []string) (*strset.Set, error) {
+	var (
+		r0 *strset.Set
+		r1 error
+	)
+	return r0, r1
+}
+
+// Fix is a mock method.
+func (mock Logic) Fix(results []domain.Result, dryRun bool, w io.Writer) error {
+	methodName := "Fix" // nolint: goconst
+	if mock.impl.Fix != nil {
+		return mock.impl.Fix(results, dryRun, w)
+	}
+	if mock.callbackNotImplemented != nil {
+		mock.callbackNotImplemented(mock.t, mock.name, methodName)
+	} else {
+		gomic.DefaultCallbackNotImplemented(mock.t, mock.name, methodName)
+	}
+	return mock.fakeZeroFix(results, dryRun, w)
+}
+
+// SetFuncFix sets a method and returns the mock.
+func (mock *Logic) SetFuncFix(impl func(results []domain.Result, dryRun bool, w io.Writer) error) *Logic {
+	mock.impl.Fix = impl
+	return mock
+}
+
+// SetReturnFix sets a fake method.
+func (mock *Logic) SetReturnFix(r0 error) *Logic {
+	mock.impl.Fix = func([]domain.Result, bool, io.Writer) error {
+		return r0
+	}
+	return mock
+}
+
+// fakeZeroFix is a fake method which returns zero values.
+func (mock Logic) fakeZeroFix(results []domain.Result, dryRun bool, w io.Writer) error {
+	var (
+		r0 error
+	)
+	return r0
+}
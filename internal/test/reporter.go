@@ -0,0 +1,103 @@
+package test
+
+// Don't edit this file.
+// This file is generated by gomic 0.5.2.
+// https://github.com/suzuki-shunsuke/gomic
+
+import (
+	testing "testing"
+
+	domain "github.com/suzuki-shunsuke/durl/internal/domain"
+	gomic "github.com/suzuki-shunsuke/gomic/gomic"
+)
+
+type (
+	// Reporter is a mock.
+	Reporter struct {
+		t                      *testing.T
+		name                   string
+		callbackNotImplemented gomic.CallbackNotImplemented
+		impl                   struct {
+			Report func(result domain.Result) error
+			Finish func(results []domain.Result) error
+		}
+	}
+)
+
+// NewReporter returns Reporter .
+func NewReporter(t *testing.T, cb gomic.CallbackNotImplemented) *Reporter {
+	return &Reporter{
+		t: t, name: "Reporter", callbackNotImplemented: cb}
+}
+
+// Report is a mock method.
+func (mock Reporter) Report(result domain.Result) error {
+	methodName := "Report" // nolint: goconst
+	if mock.impl.Report != nil {
+		return mock.impl.Report(result)
+	}
+	if mock.callbackNotImplemented != nil {
+		mock.callbackNotImplemented(mock.t, mock.name, methodName)
+	} else {
+		gomic.DefaultCallbackNotImplemented(mock.t, mock.name, methodName)
+	}
+	return mock.fakeZeroReport(result)
+}
+
+// SetFuncReport sets a method and returns the mock.
+func (mock *Reporter) SetFuncReport(impl func(result domain.Result) error) *Reporter {
+	mock.impl.Report = impl
+	return mock
+}
+
+// SetReturnReport sets a fake method.
+func (mock *Reporter) SetReturnReport(r0 error) *Reporter {
+	mock.impl.Report = func(domain.Result) error {
+		return r0
+	}
+	return mock
+}
+
+// fakeZeroReport is a fake method which returns zero values.
+func (mock Reporter) fakeZeroReport(result domain.Result) error {
+	var (
+		r0 error
+	)
+	return r0
+}
+
+// Finish is a mock method.
+func (mock Reporter) Finish(results []domain.Result) error {
+	methodName := "Finish" // nolint: goconst
+	if mock.impl.Finish != nil {
+		return mock.impl.Finish(results)
+	}
+	if mock.callbackNotImplemented != nil {
+		mock.callbackNotImplemented(mock.t, mock.name, methodName)
+	} else {
+		gomic.DefaultCallbackNotImplemented(mock.t, mock.name, methodName)
+	}
+	return mock.fakeZeroFinish(results)
+}
+
+// SetFuncFinish sets a method and returns the mock.
+func (mock *Reporter) SetFuncFinish(impl func(results []domain.Result) error) *Reporter {
+	mock.impl.Finish = impl
+	return mock
+}
+
+// SetReturnFinish sets a fake method.
+func (mock *Reporter) SetReturnFinish(r0 error) *Reporter {
+	mock.impl.Finish = func([]domain.Result) error {
+		return r0
+	}
+	return mock
+}
+
+// fakeZeroFinish is a fake method which returns zero values.
+func (mock Reporter) fakeZeroFinish(results []domain.Result) error {
+	var (
+		r0 error
+	)
+	return r0
+}
@@ -0,0 +1,68 @@
+package test
+
+// Don't edit this file.
+// This file is generated by gomic 0.5.2.
+// https://github.com/suzuki-shunsuke/gomic
+
+import (
+	"context"
+	"net"
+	testing "testing"
+
+	gomic "github.com/suzuki-shunsuke/gomic/gomic"
+)
+
+type (
+	// Resolver is a mock.
+	Resolver struct {
+		t                      *testing.T
+		name                   string
+		callbackNotImplemented gomic.CallbackNotImplemented
+		impl                   struct {
+			LookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+		}
+	}
+)
+
+// NewResolver returns Resolver .
+func NewResolver(t *testing.T, cb gomic.CallbackNotImplemented) *Resolver {
+	return &Resolver{
+		t: t, name: "Resolver", callbackNotImplemented: cb}
+}
+
+// LookupIPAddr is a mock method.
+func (mock Resolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	methodName := "LookupIPAddr" // nolint: goconst
+	if mock.impl.LookupIPAddr != nil {
+		return mock.impl.LookupIPAddr(ctx, host)
+	}
+	if mock.callbackNotImplemented != nil {
+		mock.callbackNotImplemented(mock.t, mock.name, methodName)
+	} else {
+		gomic.DefaultCallbackNotImplemented(mock.t, mock.name, methodName)
+	}
+	return mock.fakeZeroLookupIPAddr(ctx, host)
+}
+
+// SetFuncLookupIPAddr sets a method and returns the mock.
+func (mock *Resolver) SetFuncLookupIPAddr(impl func(ctx context.Context, host string) ([]net.IPAddr, error)) *Resolver {
+	mock.impl.LookupIPAddr = impl
+	return mock
+}
+
+// SetReturnLookupIPAddr sets a fake method.
+func (mock *Resolver) SetReturnLookupIPAddr(r0 []net.IPAddr, r1 error) *Resolver {
+	mock.impl.LookupIPAddr = func(context.Context, string) ([]net.IPAddr, error) {
+		return r0, r1
+	}
+	return mock
+}
+
+// fakeZeroLookupIPAddr is a fake method which returns zero values.
+func (mock Resolver) fakeZeroLookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	var (
+		r0 []net.IPAddr
+		r1 error
+	)
+	return r0, r1
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+const (
+	// maxAnchorPageSize is the max size of a page which is parsed to find anchors.
+	maxAnchorPageSize = 10 * 1024 * 1024
+	// githubAnchorPrefix is the prefix of ids which GitHub adds to anchors in rendered Markdown.
+	githubAnchorPrefix = "user-content-"
+)
+
+var (
+	// atxHeading matches an ATX heading such as "## Install ##".
+	atxHeading = regexp.MustCompile(`^ {0,3}#{1,6}(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`) //nolint:gochecknoglobals
+	// setextUnderline matches an underline of a setext heading such as "===" and "---".
+	setextUnderline = regexp.MustCompile(`^ {0,3}(?:=+|-+)[ \t]*$`) //nolint:gochecknoglobals
+	// markdownLinkText matches an inline link, an image and a reference link and the submatch is the link text.
+	markdownLinkText = regexp.MustCompile(`!?\[([^\]]*)\](?:\([^)]*\)|\[[^\]]*\])`) //nolint:gochecknoglobals
+	// htmlTag matches a HTML tag.
+	htmlTag = regexp.MustCompile(`<[^>]*>`) //nolint:gochecknoglobals
+	// htmlAnchorAttr matches an id or name attribute in a HTML tag of a Markdown document.
+	htmlAnchorAttr = regexp.MustCompile(`<[^>]*\s(?:id|name)\s*=\s*["']([^"']+)["']`) //nolint:gochecknoglobals
+)
+
+// getFragment returns the decoded fragment of the url.
+// Text fragments such as "#:~:text=foo" aren't anchors, so an empty string is returned.
+func getFragment(u string) string {
+	a, err := url.Parse(u)
+	if err != nil || strings.HasPrefix(a.Fragment, ":~:") {
+		return ""
+	}
+	return a.Fragment
+}
+
+// checkURLWithAnchor gets a HTML page by the GET method and checks whether the anchor exists in the page.
+func (lgc *logic) checkURLWithAnchor(ctx context.Context, u, fragment string) domain.Result {
+	result, resp := lgc.requestWithRetry(ctx, u, http.MethodGet)
+	if resp == nil {
+		return result
+	}
+	defer resp.Body.Close()
+	if result.Failed() {
+		return result
+	}
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil &&
+		mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		// anchors of the other media type such as "#page=2" of PDF aren't checked
+		return result
+	}
+	anchors, err := getHTMLAnchors(io.LimitReader(resp.Body, maxAnchorPageSize))
+	if err != nil {
+		result.ErrorKind = getErrorKind(err)
+		result.Error = fmt.Sprintf("failed to read the page %s: %s", u, err)
+		return result
+	}
+	if !hasAnchor(anchors, fragment) {
+		result.ErrorKind = domain.ErrorKindAnchor
+		result.Error = fmt.Sprintf("the anchor #%s isn't found in %s", fragment, u)
+	}
+	return result
+}
+
+// hasAnchor returns true if the anchor is found.
+// The anchor with the prefix "user-content-" matches the anchor without the prefix, and vice versa.
+func hasAnchor(anchors map[string]struct{}, fragment string) bool {
+	for _, a := range []string{fragment, strings.TrimPrefix(fragment, githubAnchorPrefix), githubAnchorPrefix + fragment} {
+		if _, ok := anchors[a]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// getHTMLAnchors returns values of id attributes and name attributes in a HTML document.
+func getHTMLAnchors(r io.Reader) (map[string]struct{}, error) {
+	anchors := map[string]struct{}{}
+	tokenizer := html.NewTokenizer(r)
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF {
+				return nil, err
+			}
+			return anchors, nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			for {
+				key, val, more := tokenizer.TagAttr()
+				if k := string(key); k == "id" || k == "name" {
+					anchors[string(val)] = struct{}{}
+				}
+				if !more {
+					break
+				}
+			}
+		}
+	}
+}
+
+// getMarkdownAnchors returns anchors of headings and id attributes and name attributes of HTML tags in a Markdown document.
+// Anchors of headings are generated by the same rule as GitHub.
+func getMarkdownAnchors(r io.Reader) (map[string]struct{}, error) {
+	anchors := map[string]struct{}{}
+	// the number of headings per slug to add the suffix to the duplicated slug
+	counts := map[string]int{}
+	addHeading := func(text string) {
+		slug := githubSlug(text)
+		if n := counts[slug]; n != 0 {
+			anchors[slug+"-"+strconv.Itoa(n)] = struct{}{}
+		} else {
+			anchors[slug] = struct{}{}
+		}
+		counts[slug]++
+	}
+	fence := ""
+	// the previous line which can be the text of a setext heading
+	paragraph := ""
+	// a long line is split into segments by scanLines, and only the first segment can be a heading or a fence
+	err := scanLines(r, func(line string, _, col int) {
+		if fence != "" {
+			if col == 1 && isClosingFence(line, fence) {
+				fence = ""
+			}
+			return
+		}
+		if m := codeFence.FindStringSubmatch(line); col == 1 && m != nil && !(m[1][0] == '`' && strings.Contains(m[2], "`")) {
+			fence = m[1]
+			paragraph = ""
+			return
+		}
+		for _, m := range htmlAnchorAttr.FindAllStringSubmatch(line, -1) {
+			anchors[m[1]] = struct{}{}
+		}
+		if col != 1 {
+			paragraph = ""
+			return
+		}
+		if m := atxHeading.FindStringSubmatch(line); m != nil {
+			addHeading(m[1])
+			paragraph = ""
+			return
+		}
+		if paragraph != "" && setextUnderline.MatchString(line) {
+			addHeading(paragraph)
+			paragraph = ""
+			return
+		}
+		paragraph = strings.TrimSpace(line)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return anchors, nil
+}
+
+// githubSlug converts the text of a heading to the anchor by the same rule as GitHub.
+// Characters except letters, marks, numbers, connector punctuations, spaces and hyphens are removed,
+// letters are converted to lower case and spaces are converted to hyphens.
+func githubSlug(text string) string {
+	text = markdownLinkText.ReplaceAllString(text, "$1")
+	text = htmlTag.ReplaceAllString(text, "")
+	buf := strings.Builder{}
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case r == ' ':
+			buf.WriteRune('-')
+		case r == '-' || unicode.In(r, unicode.L, unicode.M, unicode.Nd, unicode.Pc):
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// getLocalAnchors returns anchors in a local Markdown or HTML file.
+// If the file is neither Markdown nor HTML, false is returned.
+func (lgc *logic) getLocalAnchors(p string) (map[string]struct{}, bool, error) {
+	ext := strings.ToLower(filepath.Ext(p))
+	_, isMarkdown := markdownExts[ext]
+	_, isHTML := htmlExts[ext]
+	if !isMarkdown && !isHTML {
+		return nil, false, nil
+	}
+	f, err := lgc.fsys.Open(p)
+	if err != nil {
+		return nil, true, err
+	}
+	defer f.Close()
+	if isMarkdown {
+		anchors, err := getMarkdownAnchors(f)
+		return anchors, true, err
+	}
+	anchors, err := getHTMLAnchors(f)
+	return anchors, true, err
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/gomic/gomic"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+	"github.com/suzuki-shunsuke/durl/internal/test"
+)
+
+func Test_githubSlug(t *testing.T) {
+	data := []struct {
+		in  string
+		exp string
+	}{
+		{"Getting Started", "getting-started"},
+		{"  `durl check` --git ", "durl-check---git"},
+		{"What's [new](https://example.com) in v1.0?", "whats-new-in-v10"},
+		{"<a name=\"foo\"></a>snake_case 日本語", "snake_case-日本語"},
+	}
+	for _, tt := range data {
+		require.Equal(t, tt.exp, githubSlug(tt.in), tt.in)
+	}
+}
+
+func Test_getMarkdownAnchors(t *testing.T) {
+	doc := strings.Join([]string{
+		"# durl",
+		"## Install ##",
+		"```sh",
+		"# not a heading",
+		"```",
+		"Overview",
+		"========",
+		"## Install",
+		"#hashtag",
+		`<a id="custom"></a>`,
+		"## Install",
+		// a line longer than the chunk of scanLines
+		strings.Repeat("a ", maxChunkSize) + `<a name="long"></a>`,
+		"## Last",
+	}, "\n")
+	anchors, err := getMarkdownAnchors(strings.NewReader(doc))
+	require.Nil(t, err)
+	require.Equal(t, map[string]struct{}{
+		"durl": {}, "install": {}, "install-1": {}, "install-2": {}, "overview": {}, "custom": {},
+		"long": {}, "last": {},
+	}, anchors)
+}
+
+func Test_logicCheckURLWithAnchor(t *testing.T) {
+	page := `<html><body><h2 id="user-content-install">Install</h2><a name="usage"></a></body></html>`
+	data := []struct {
+		title       string
+		url         string
+		contentType string
+		kind        string
+	}{
+		{"id", "https://github.com/suzuki-shunsuke/durl#install", "text/html; charset=utf-8", ""},
+		{"name", "https://github.com/suzuki-shunsuke/durl#usage", "text/html", ""},
+		{"not found", "https://github.com/suzuki-shunsuke/durl#hoge", "text/html", domain.ErrorKindAnchor},
+		{"not html", "https://example.com/foo.pdf#page=2", "application/pdf", ""},
+		{"text fragment", "https://github.com/suzuki-shunsuke/durl#:~:text=hoge", "text/html", ""},
+	}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			lgc := &logic{
+				cfg: domain.Cfg{CheckAnchors: true, HTTPMethod: "head"},
+				client: test.NewHTTPClient(t, gomic.DoNothing).
+					SetFuncDo(func(req *http.Request) (*http.Response, error) {
+						return &http.Response{
+							Body:       ioutil.NopCloser(bytes.NewBufferString(page)),
+							StatusCode: 200,
+							Header:     http.Header{"Content-Type": []string{tt.contentType}},
+						}, nil
+					}),
+			}
+			lgc.logic = lgc
+			result := lgc.CheckURL(context.Background(), tt.url)
+			require.Equal(t, tt.kind, result.ErrorKind, result.Error)
+		})
+	}
+}
+
+func Test_logicCheckLocalLinkAnchor(t *testing.T) {
+	files := map[string]string{
+		"README.md":       "# durl\n## Install\n",
+		"docs/index.html": `<h1 id="top">Top</h1>`,
+		"main.go":         "package main",
+	}
+	lgc := &logic{
+		cfg: domain.Cfg{CheckAnchors: true},
+		fsys: test.NewFsys(t, nil).
+			SetFuncExist(func(p string) bool {
+				_, ok := files[p]
+				return ok
+			}).
+			SetFuncOpen(func(p string) (io.ReadCloser, error) {
+				return ioutil.NopCloser(strings.NewReader(files[p])), nil
+			}),
+	}
+	urls := map[string][]domain.Location{
+		"#install":                  {{Path: "README.md"}},
+		"README.md#getting-started": {{Path: "docs/index.md"}},
+		"index.html#top":            {{Path: "docs/foo.html"}},
+		"../main.go#L10":            {{Path: "docs/foo.md"}},
+	}
+	links := lgc.extractLocalLinks(urls)
+	exp := map[string]string{
+		"README.md#install":              "",
+		"docs/README.md#getting-started": domain.ErrorKindNotFound,
+		"docs/index.html#top":            "",
+		"main.go#L10":                    "",
+	}
+	require.Len(t, links, len(exp))
+	for link, kind := range exp {
+		require.Contains(t, links, link)
+		require.Equal(t, kind, lgc.checkLocalLink(link).ErrorKind, link)
+	}
+	require.Equal(t, domain.ErrorKindAnchor, lgc.checkLocalLink("README.md#getting-started").ErrorKind)
+}
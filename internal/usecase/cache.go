@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// cacheVersion is the version of the cache file format.
+// If the format is changed incompatibly, the version is incremented and the old cache is discarded.
+const cacheVersion = 1
+
+type (
+	// resultCache caches results of urls. The methods of nil are no-op.
+	resultCache struct {
+		mutex      sync.Mutex
+		entries    map[string]cacheEntry
+		ttl        time.Duration
+		failureTTL time.Duration
+		now        func() time.Time
+	}
+
+	cacheFile struct {
+		Version int `json:"version"`
+		// Fingerprint is the hash of the settings which affect results.
+		Fingerprint string                `json:"fingerprint"`
+		Entries     map[string]cacheEntry `json:"entries"`
+	}
+
+	cacheEntry struct {
+		CheckedAt    time.Time      `json:"checked_at"`
+		Method       string         `json:"method,omitempty"`
+		StatusCode   int            `json:"status_code,omitempty"`
+		ErrorKind    string         `json:"error_kind,omitempty"`
+		Error        string         `json:"error,omitempty"`
+		Redirects    []jsonRedirect `json:"redirects,omitempty"`
+		SuggestedURL string         `json:"suggested_url,omitempty"`
+		Warning      string         `json:"warning,omitempty"`
+	}
+)
+
+// readCache reads the cache file. If the cache is disabled or cache_file isn't set, nil is returned.
+// If the cache file is broken, the cache is discarded instead of failing.
+func (lgc *logic) readCache() *resultCache {
+	if lgc.cfg.NoCache || lgc.cfg.CacheFile == "" {
+		return nil
+	}
+	c := &resultCache{
+		entries:    map[string]cacheEntry{},
+		ttl:        lgc.cfg.CacheTTL,
+		failureTTL: lgc.cfg.CacheFailureTTL,
+		now:        time.Now,
+	}
+	if !lgc.fsys.Exist(lgc.cfg.CacheFile) {
+		return c
+	}
+	f, err := lgc.fsys.Open(lgc.cfg.CacheFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[WARN] failed to open the cache file %s: %s\n", lgc.cfg.CacheFile, err)
+		return c
+	}
+	defer f.Close()
+	file := cacheFile{}
+	if err := json.NewDecoder(f).Decode(&file); err != nil {
+		fmt.Fprintf(os.Stderr, "[WARN] discard the broken cache file %s: %s\n", lgc.cfg.CacheFile, err)
+		return c
+	}
+	if file.Version != cacheVersion || file.Fingerprint != cacheFingerprint(lgc.cfg) {
+		// the cached results may be changed by the current settings
+		return c
+	}
+	for u, entry := range file.Entries {
+		if !c.expired(entry) {
+			c.entries[u] = entry
+		}
+	}
+	return c
+}
+
+// writeCache writes unexpired results to the cache file.
+func (lgc *logic) writeCache(c *resultCache) error {
+	if c == nil {
+		return nil
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	file := cacheFile{
+		Version:     cacheVersion,
+		Fingerprint: cacheFingerprint(lgc.cfg),
+		Entries:     make(map[string]cacheEntry, len(c.entries)),
+	}
+	for u, entry := range c.entries {
+		if !c.expired(entry) {
+			file.Entries[u] = entry
+		}
+	}
+	b, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	if err := lgc.fsys.Write(lgc.cfg.CacheFile, b); err != nil {
+		return fmt.Errorf("failed to write the cache file %s: %w", lgc.cfg.CacheFile, err)
+	}
+	return nil
+}
+
+// cacheFingerprint returns the hash of the settings which affect results of urls,
+// so that the cache is discarded when the settings are changed.
+func cacheFingerprint(cfg domain.Cfg) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%q %q %t %q %d\n", cfg.HTTPMethod, cfg.AcceptedStatusCodes, cfg.CheckAnchors, cfg.Redirects, cfg.MaxRedirects)
+	for _, rule := range cfg.AcceptedStatusCodeRules {
+		fmt.Fprintf(h, "%q %q %q\n", rule.Host, rule.URL, rule.StatusCodes)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// isCacheFile returns true if the path is the cache file.
+// The cache file is written in the working directory by default, and it must not be checked as a source
+// because it includes urls of results.
+func (lgc *logic) isCacheFile(p string) bool {
+	if lgc.cfg.CacheFile == "" {
+		return false
+	}
+	a, b := filepath.Clean(p), filepath.Clean(lgc.cfg.CacheFile)
+	if a == b {
+		return true
+	}
+	if filepath.IsAbs(a) == filepath.IsAbs(b) {
+		return false
+	}
+	absA, err := filepath.Abs(a)
+	if err != nil {
+		return false
+	}
+	absB, err := filepath.Abs(b)
+	return err == nil && absA == absB
+}
+
+func (c *resultCache) expired(entry cacheEntry) bool {
+	ttl := c.ttl
+	if entry.ErrorKind != "" {
+		ttl = c.failureTTL
+	}
+	return c.now().Sub(entry.CheckedAt) >= ttl
+}
+
+// get returns the cached result of the url.
+// Only results of http and https urls are cached, because local links are checked quickly.
+func (c *resultCache) get(u string) (domain.Result, bool) {
+	if c == nil || !isHTTPURL(u) {
+		return domain.Result{}, false
+	}
+	c.mutex.Lock()
+	entry, ok := c.entries[normalizeURL(u)]
+	c.mutex.Unlock()
+	if !ok || c.expired(entry) {
+		return domain.Result{}, false
+	}
+	var redirects []domain.Redirect
+	for _, redirect := range entry.Redirects {
+		redirects = append(redirects, domain.Redirect{StatusCode: redirect.StatusCode, URL: redirect.URL})
+	}
+	return domain.Result{
+		URL:          u,
+		Method:       entry.Method,
+		StatusCode:   entry.StatusCode,
+		ErrorKind:    entry.ErrorKind,
+		Error:        entry.Error,
+		Redirects:    redirects,
+		SuggestedURL: entry.SuggestedURL,
+		Warning:      entry.Warning,
+		Cached:       true,
+	}, true
+}
+
+func (c *resultCache) set(u string, result domain.Result) {
+	if c == nil || !isHTTPURL(u) {
+		return
+	}
+	entry := cacheEntry{
+		CheckedAt:    c.now(),
+		Method:       result.Method,
+		StatusCode:   result.StatusCode,
+		ErrorKind:    result.ErrorKind,
+		Error:        result.Error,
+		SuggestedURL: result.SuggestedURL,
+		Warning:      result.Warning,
+	}
+	for _, redirect := range result.Redirects {
+		entry.Redirects = append(entry.Redirects, jsonRedirect{StatusCode: redirect.StatusCode, URL: redirect.URL})
+	}
+	c.mutex.Lock()
+	c.entries[normalizeURL(u)] = entry
+	c.mutex.Unlock()
+}
+
+// normalizeURL returns the key of the cache.
+// The scheme and the host are converted to lower case and the default port is removed.
+// The fragment is kept because the result such as the error message and the suggested url depends on it.
+func normalizeURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if (u.Scheme == "http" && u.Port() == "80") || (u.Scheme == "https" && u.Port() == "443") {
+		u.Host = u.Hostname()
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
+
+// initCache validates the settings of the cache and sets the default values.
+func initCache(cfg domain.Cfg) (domain.Cfg, error) {
+	if cfg.CacheFile == "" {
+		cfg.CacheFile = domain.DefaultCacheFile
+	}
+	if cfg.CacheTTL < 0 || cfg.CacheFailureTTL < 0 {
+		return cfg, fmt.Errorf(`cache_ttl and cache_failure_ttl must not be negative`)
+	}
+	if cfg.CacheTTL == 0 {
+		cfg.CacheTTL = domain.DefaultCacheTTL
+	}
+	if cfg.CacheFailureTTL == 0 {
+		cfg.CacheFailureTTL = domain.DefaultCacheFailureTTL
+	}
+	return cfg, nil
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/gomic/gomic"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+	"github.com/suzuki-shunsuke/durl/internal/test"
+)
+
+func Test_normalizeURL(t *testing.T) {
+	data := []struct {
+		title string
+		url   string
+		exp   string
+	}{
+		{"normal", "https://github.com/suzuki-shunsuke/durl", "https://github.com/suzuki-shunsuke/durl"},
+		{"case", "HTTPS://GitHub.com/suzuki-shunsuke/durl", "https://github.com/suzuki-shunsuke/durl"},
+		{"default port", "https://github.com:443", "https://github.com/"},
+		{"other port", "http://example.org:8080/foo", "http://example.org:8080/foo"},
+		{"fragment", "https://GitHub.com/suzuki-shunsuke/durl#Install", "https://github.com/suzuki-shunsuke/durl#Install"},
+	}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			require.Equal(t, tt.exp, normalizeURL(tt.url))
+		})
+	}
+}
+
+func Test_logicCache(t *testing.T) {
+	now := time.Now()
+	cfg := domain.Cfg{CacheFile: ".durl-cache", CacheTTL: 24 * time.Hour, CacheFailureTTL: time.Hour}
+	written := map[string][]byte{}
+	fsys := test.NewFsys(t, nil).
+		SetFuncExist(func(p string) bool {
+			_, ok := written[p]
+			return ok
+		}).
+		SetFuncOpen(func(p string) (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(written[p])), nil
+		}).
+		SetFuncWrite(func(p string, b []byte) error {
+			written[p] = b
+			return nil
+		})
+	lgc := &logic{cfg: cfg, fsys: fsys}
+
+	c := lgc.readCache()
+	require.NotNil(t, c)
+	c.now = func() time.Time { return now }
+	redirects := []domain.Redirect{{StatusCode: 301, URL: "https://github.com/foo"}}
+	c.set("https://GitHub.com/foo", domain.Result{Method: "HEAD", StatusCode: 200, Redirects: redirects})
+	c.set("https://github.com/dead", domain.Result{
+		Method: "GET", StatusCode: 404, ErrorKind: domain.ErrorKindHTTPClientError, Error: "status code 404",
+	})
+	c.set("docs/setup.md", domain.Result{})
+	require.Nil(t, lgc.writeCache(c))
+
+	c = lgc.readCache()
+	c.now = func() time.Time { return now.Add(2 * time.Hour) }
+	result, ok := c.get("https://github.com:443/foo")
+	require.True(t, ok)
+	require.Equal(t, domain.Result{
+		URL: "https://github.com:443/foo", Method: "HEAD", StatusCode: 200, Redirects: redirects, Cached: true,
+	}, result)
+	// the failure is expired
+	_, ok = c.get("https://github.com/dead")
+	require.False(t, ok)
+	// local links aren't cached
+	_, ok = c.get("docs/setup.md")
+	require.False(t, ok)
+
+	// the cache is discarded if the settings which affect results are changed
+	lgc.cfg.CheckAnchors = true
+	require.Empty(t, lgc.readCache().entries)
+	lgc.cfg.CheckAnchors = false
+	require.NotEmpty(t, lgc.readCache().entries)
+
+	c.now = func() time.Time { return now.Add(25 * time.Hour) }
+	require.Nil(t, lgc.writeCache(c))
+	require.Empty(t, lgc.readCache().entries)
+
+	lgc.cfg.NoCache = true
+	require.Nil(t, lgc.readCache())
+}
+
+func Test_logicCheckURLsCache(t *testing.T) {
+	c := &resultCache{
+		entries: map[string]cacheEntry{},
+		ttl:     time.Hour,
+		now:     time.Now,
+	}
+	c.set("https://github.com/foo", domain.Result{Method: "HEAD", StatusCode: 200})
+	checked := []string{}
+	lgc := &logic{
+		cfg:      domain.Cfg{MaxFailedRequestCount: -1},
+		reporter: test.NewReporter(t, gomic.DoNothing),
+		cache:    c,
+		logic: test.NewLogic(t, nil).SetFuncCheckURL(func(ctx context.Context, u string) domain.Result {
+			checked = append(checked, u)
+			return domain.Result{Method: "HEAD", StatusCode: 200}
+		}),
+	}
+	results, err := lgc.CheckURLs(map[string][]domain.Location{
+		"https://github.com/foo": {{Path: "README.md"}},
+		"https://github.com/bar": {{Path: "README.md"}},
+	})
+	require.Nil(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, []string{"https://github.com/bar"}, checked)
+	_, ok := c.get("https://github.com/bar")
+	require.True(t, ok)
+}
+
+func Test_logicCheckCacheFileIsntChecked(t *testing.T) {
+	// the cache file is written to the same directory as sources, but it isn't checked at the next run
+	files := map[string][]byte{
+		"README.md": []byte("https://github.com/suzuki-shunsuke/dead\n"),
+	}
+	fsys := test.NewFsys(t, nil).
+		SetFuncExist(func(p string) bool {
+			_, ok := files[p]
+			return ok
+		}).
+		SetFuncOpen(func(p string) (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(files[p])), nil
+		}).
+		SetFuncWrite(func(p string, b []byte) error {
+			files[p] = b
+			return nil
+		}).
+		SetFuncWalk(func(root string, fn filepath.WalkFunc) error {
+			if err := fn(root, fileInfo{name: root, isDir: true}, nil); err != nil {
+				return err
+			}
+			paths := make([]string, 0, len(files))
+			for p := range files {
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+			for _, p := range paths {
+				if err := fn(p, fileInfo{name: p, size: int64(len(files[p]))}, nil); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	client := test.NewHTTPClient(t, nil).SetFuncDo(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       ioutil.NopCloser(&bytes.Buffer{}),
+			Request:    req,
+		}, nil
+	})
+	reader := &cfgReader{}
+	cfg, err := reader.InitCfg(domain.Cfg{MaxFailedRequestCount: -1})
+	require.Nil(t, err)
+	lgc := NewLogic(cfg, fsys, client, nil, test.NewReporter(t, gomic.DoNothing))
+	for i := 0; i < 2; i++ {
+		results, err := lgc.Check(nil, []string{"."})
+		require.NotNil(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, []domain.Location{{Path: "README.md", Line: 1, Column: 1}}, results[0].Locations)
+		require.Equal(t, i == 1, results[0].Cached)
+	}
+	require.Contains(t, files, cfg.CacheFile)
+}
@@ -5,40 +5,84 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
-	"mvdan.cc/xurls/v2"
-
 	"github.com/scylladb/go-set/strset"
 
 	"github.com/suzuki-shunsuke/durl/internal/domain"
 )
 
-func (lgc *logic) Check(stdin io.Reader, cfgPath string) error {
-	// get file paths
-	files, err := lgc.logic.GetFiles(stdin)
-	if err != nil {
-		return err
-	}
+func (lgc *logic) Check(stdin io.Reader, paths []string) ([]domain.Result, error) {
 	// urls is a map whose key is url and value is file paths which include the url
 	// url -> file paths
-	urls, err := lgc.logic.ExtractURLsFromFiles(files)
+	urls, err := lgc.getURLs(stdin, paths)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	// expand "${VAR}" and skip urls with template syntax such as "{{ .Owner }}"
+	lgc.expandURLs(urls)
+	// relative links are checked as local files
+	localLinks := lgc.extractLocalLinks(urls)
 	// filter url
 	for u := range urls {
 		if lgc.logic.IsIgnoredURL(u) {
 			delete(urls, u)
 		}
 	}
+	// hosts are resolved after ignoring urls so that ignored hosts aren't resolved
+	lgc.filterResolvedURLs(urls)
+	for p, locs := range localLinks {
+		urls[p] = locs
+	}
+
+	lgc.cache = lgc.readCache()
+	results, err := lgc.logic.CheckURLs(urls)
+	if cacheErr := lgc.writeCache(lgc.cache); cacheErr != nil {
+		fmt.Fprintf(os.Stderr, "[WARN] %s\n", cacheErr)
+	}
+	return results, err
+}
+
+func (lgc *logic) getURLs(stdin io.Reader, paths []string) (map[string][]domain.Location, error) {
+	if lgc.cfg.DiffBase != "" {
+		// check only urls in lines which are added or modified
+		return lgc.getURLsFromDiff(paths)
+	}
+	// get file paths
+	files, err := lgc.getFiles(stdin, paths)
+	if err != nil {
+		return nil, err
+	}
+	return lgc.logic.ExtractURLsFromFiles(files)
+}
 
-	return lgc.logic.CheckURLs(urls)
+func (lgc *logic) getFiles(stdin io.Reader, paths []string) (*strset.Set, error) {
+	if lgc.cfg.FileSource == domain.FileSourceGit {
+		return lgc.logic.ListGitFiles(paths)
+	}
+	if len(paths) != 0 {
+		return lgc.logic.FindFiles(paths)
+	}
+	files, err := lgc.logic.GetFiles(stdin)
+	if err != nil || files == nil {
+		return files, err
+	}
+	// filter file paths given by stdin with include and exclude patterns
+	files.Each(func(p string) bool {
+		if !lgc.isTargetFile(p) {
+			files.Remove(p)
+		}
+		return true
+	})
+	return files, nil
 }
 
 func (lgc *logic) IsIgnoredURL(uri string) bool {
@@ -51,102 +95,167 @@ func (lgc *logic) IsIgnoredURL(uri string) bool {
 	if u.Scheme != "http" && u.Scheme != "https" {
 		return true
 	}
-	for _, ignoreHost := range domain.IgnoreHosts {
-		if u.Host == ignoreHost || strings.HasPrefix(u.Host, fmt.Sprintf("%s:", ignoreHost)) {
-			return true
-		}
-	}
 	for _, u := range lgc.cfg.IgnoreURLs {
 		if uri == u {
 			return true
 		}
 	}
-	for _, h := range lgc.cfg.IgnoreHosts {
-		if u.Host == h {
-			return true
-		}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && lgc.isIgnoredIP(ip) {
+		return true
 	}
-	return false
+	return matchPatterns(lgc.cfg.IgnoreURLRegexps, uri) || matchPatterns(lgc.cfg.IgnoreHostRegexps, strings.ToLower(u.Host))
 }
 
-func (lgc *logic) CheckURLs(urls map[string]*strset.Set) error {
+func (lgc *logic) CheckURLs(urls map[string][]domain.Location) ([]domain.Result, error) {
 	if len(urls) == 0 {
-		return nil
+		return nil, nil
 	}
 	if lgc.cfg.MaxRequestCount == 0 {
 		lgc.cfg.MaxRequestCount = domain.DefaultMaxRequestCount
 	}
 	semaphore := make(chan struct{}, lgc.cfg.MaxRequestCount)
-	resultChan := make(chan error, len(urls))
+	resultChan := make(chan domain.Result, len(urls))
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	for u, files := range urls {
-		go func(u string, files *strset.Set) {
-			semaphore <- struct{}{}
-			err := lgc.logic.CheckURL(ctx, u)
-			<-semaphore
-			if err == nil {
-				resultChan <- nil
+	for u, locs := range urls {
+		go func(u string, locs []domain.Location) {
+			result, ok := lgc.checkURLWithSlots(ctx, u, semaphore)
+			if !ok {
 				return
 			}
-			resultChan <- fmt.Errorf("failed to check a url %s %s: %w", u, files.String(), err)
-		}(u, files)
+			result.URL = u
+			result.Locations = make([]domain.Location, len(locs))
+			copy(result.Locations, locs)
+			domain.SortLocations(result.Locations)
+			resultChan <- result
+		}(u, locs)
 	}
-	endCount := len(urls)
-	failedCount := lgc.cfg.MaxFailedRequestCount
+	results := make([]domain.Result, 0, len(urls))
+	failedCount := 0
 	for {
 		select {
-		case err := <-resultChan:
-			endCount--
-			if err != nil {
-				failedCount--
-				fmt.Fprintln(os.Stderr, err)
+		case result := <-resultChan:
+			results = append(results, result)
+			if err := lgc.reporter.Report(result); err != nil {
+				return results, err
+			}
+			if result.Failed() {
+				failedCount++
 			}
-			if endCount == 0 {
-				if failedCount != lgc.cfg.MaxFailedRequestCount {
-					return fmt.Errorf("")
+			if len(results) == len(urls) {
+				if failedCount != 0 {
+					return results, fmt.Errorf("%d urls are dead", failedCount)
 				}
-				return nil
+				return results, nil
 			}
-			if lgc.cfg.MaxFailedRequestCount != -1 && failedCount == -1 {
-				return fmt.Errorf("too many urls are dead")
+			if lgc.cfg.MaxFailedRequestCount != -1 && failedCount > lgc.cfg.MaxFailedRequestCount {
+				return results, fmt.Errorf("too many urls are dead")
 			}
 		case <-ctx.Done():
-			return fmt.Errorf("context is caceled")
+			return results, fmt.Errorf("context is caceled")
 		}
 	}
 }
 
+// checkURLWithSlots returns the cached result of the url, or checks the url holding the slot of the host
+// and the global slot of max_request_count.
+// If the context is canceled while waiting for the slots, false is returned.
+func (lgc *logic) checkURLWithSlots(ctx context.Context, u string, semaphore chan struct{}) (domain.Result, bool) {
+	if result, ok := lgc.cache.get(u); ok {
+		return result, true
+	}
+	// acquire the slot of the host before the global slot
+	// so that requests waiting for the busy host don't block requests to the other hosts
+	host := getHost(u)
+	scheduler := lgc.getHostScheduler(host)
+	if !scheduler.acquire(ctx) {
+		return domain.Result{}, false
+	}
+	defer scheduler.release()
+	// wait while requests to the host are paused by Retry-After before acquiring the global slot
+	if _, ok := lgc.waitHost(ctx, host, lgc.cfg.RetryAfterMaxWait); !ok {
+		return domain.Result{}, false
+	}
+	semaphore <- struct{}{}
+	// the slot is released while the request waits for the host
+	result := lgc.logic.CheckURL(withGlobalSlot(ctx, semaphore), u)
+	<-semaphore
+	// the result of the canceled request isn't cached
+	if ctx.Err() == nil {
+		lgc.cache.set(u, result)
+	}
+	return result, true
+}
+
 func (lgc *logic) CheckURLWithMethod(
 	ctx context.Context, u, method string,
-) error {
+) domain.Result {
+	result, resp := lgc.requestWithRetry(ctx, u, method)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return result
+}
+
+// request sends a HTTP request and returns the result and the response.
+// If it is failed to send the request, the response is nil.
+// Otherwise the caller must close the response body.
+func (lgc *logic) request(ctx context.Context, u, method string) (domain.Result, *http.Response) {
+	result := domain.Result{
+		URL:    u,
+		Method: method,
+	}
 	req, err := http.NewRequest(method, u, nil)
 	if err != nil {
-		return err
+		result.ErrorKind = domain.ErrorKindInvalid
+		result.Error = err.Error()
+		return result, nil
 	}
 	req = req.WithContext(ctx)
+	start := time.Now()
 	resp, err := lgc.client.Do(req)
+	result.Duration = time.Since(start)
 	if err != nil {
-		return err
+		result.ErrorKind = getErrorKind(err)
+		result.Error = err.Error()
+		return result, nil
 	}
-	resp.Body.Close()
+	result.StatusCode = resp.StatusCode
+	result.Redirects = getRedirects(resp)
 	// check status code
-	if resp.StatusCode/100 != 2 { //nolint:gomnd
-		return fmt.Errorf("%s is dead (%d)", u, resp.StatusCode)
+	if !lgc.isAcceptedStatusCode(u, resp.StatusCode) {
+		result.ErrorKind = getStatusErrorKind(resp.StatusCode)
+		result.Error = fmt.Sprintf("%s is dead (%d)", u, resp.StatusCode)
+		if resp.StatusCode/100 == 3 && resp.Header.Get("Location") != "" { //nolint:gomnd
+			// the client stops following redirects after max_redirects redirects
+			result.ErrorKind = domain.ErrorKindRedirect
+			result.Error = fmt.Sprintf("%s is redirected more than %d times", u, len(result.Redirects))
+		}
 	}
-	return nil
+	lgc.applyRedirectPolicy(&result)
+	return result, resp
 }
 
-func (lgc *logic) CheckURL(ctx context.Context, u string) error {
-	switch lgc.cfg.HTTPMethod {
-	case "head,get":
-		if err := lgc.logic.CheckURLWithMethod(ctx, u, http.MethodHead); err == nil {
-			return nil
+func (lgc *logic) CheckURL(ctx context.Context, u string) domain.Result {
+	start := time.Now()
+	result := lgc.checkURL(ctx, u)
+	result.Duration = time.Since(start)
+	return result
+}
+
+func (lgc *logic) checkURL(ctx context.Context, u string) domain.Result {
+	if !isHTTPURL(u) {
+		return lgc.checkLocalLink(u)
+	}
+	if lgc.cfg.CheckAnchors {
+		if fragment := getFragment(u); fragment != "" {
+			return lgc.checkURLWithAnchor(ctx, u, fragment)
 		}
-		return lgc.logic.CheckURLWithMethod(ctx, u, http.MethodGet)
-	case "":
-		if err := lgc.logic.CheckURLWithMethod(ctx, u, http.MethodHead); err == nil {
-			return nil
+	}
+	switch lgc.cfg.HTTPMethod {
+	case "head,get", "":
+		if result := lgc.logic.CheckURLWithMethod(ctx, u, http.MethodHead); !result.Failed() {
+			return result
 		}
 		return lgc.logic.CheckURLWithMethod(ctx, u, http.MethodGet)
 	case "get":
@@ -154,35 +263,36 @@ func (lgc *logic) CheckURL(ctx context.Context, u string) error {
 	case "head":
 		return lgc.logic.CheckURLWithMethod(ctx, u, http.MethodHead)
 	default:
-		return fmt.Errorf(`invalid http_method_type: %s`, lgc.cfg.HTTPMethod)
+		return domain.Result{
+			URL:       u,
+			ErrorKind: domain.ErrorKindInvalid,
+			Error:     fmt.Sprintf(`invalid http_method_type: %s`, lgc.cfg.HTTPMethod),
+		}
 	}
 }
 
-func (lgc *logic) ExtractURLsFromFiles(files *strset.Set) (map[string]*strset.Set, error) {
-	// return a map whose key is url and value is file paths which include the url
+func (lgc *logic) ExtractURLsFromFiles(files *strset.Set) (map[string][]domain.Location, error) {
+	// return a map whose key is url and value is locations of the url
 	size := files.Size()
 	if size == 0 {
 		return nil, nil
 	}
 	// extract urls from all files
-	type (
-		File struct {
-			path string
-			urls *strset.Set
-		}
-	)
-	urlsChan := make(chan File, size)
+	linksChan := make(chan []domain.Link, size)
 	eg, ctx := errgroup.WithContext(context.Background())
 	files.Each(func(p string) bool {
 		eg.Go(func() error {
+			if lgc.isTooLargeFile(p) {
+				return nil
+			}
 			// open a file and extract urls from it
-			arr, err := lgc.logic.ExtractURLsFromFile(ctx, p)
+			links, err := lgc.logic.ExtractURLsFromFile(ctx, p)
 			if err != nil {
 				// https://github.com/suzuki-shunsuke/durl/issues/27
 				fmt.Fprintf(os.Stderr, "failed to extract urls from a file %s: %s\n", p, err)
 				return nil
 			}
-			urlsChan <- File{path: p, urls: arr}
+			linksChan <- links
 			return nil
 		})
 		return true
@@ -191,53 +301,62 @@ func (lgc *logic) ExtractURLsFromFiles(files *strset.Set) (map[string]*strset.Se
 		return nil, err
 	}
 
-	close(urlsChan)
-	// url -> file paths
-	urls := map[string]*strset.Set{}
-	for f := range urlsChan {
-		f := f
-		f.urls.Each(func(u string) bool {
-			v, ok := urls[u]
-			if ok {
-				v.Add(f.path)
-				return true
-			}
-			urls[u] = strset.New(f.path)
-			return true
-		})
+	close(linksChan)
+	// url -> locations
+	urls := map[string][]domain.Location{}
+	for links := range linksChan {
+		for _, link := range links {
+			urls[link.URL] = append(urls[link.URL], link.Location)
+		}
 	}
 	return urls, nil
 }
 
-func (lgc *logic) ExtractURLsFromFile(ctx context.Context, p string) (*strset.Set, error) {
+// isTooLargeFile returns true if the file size exceeds max_file_size.
+// The too large file is skipped with a warning.
+func (lgc *logic) isTooLargeFile(p string) bool {
+	if lgc.cfg.MaxFileSize <= 0 {
+		return false
+	}
+	info, err := lgc.fsys.Stat(p)
+	if err != nil || info.Size() <= lgc.cfg.MaxFileSize {
+		return false
+	}
+	fmt.Fprintf(
+		os.Stderr, "[WARN] skip a file %s because the file size (%d bytes) exceeds max_file_size (%d bytes)\n",
+		p, info.Size(), lgc.cfg.MaxFileSize)
+	return true
+}
+
+func (lgc *logic) ExtractURLsFromFile(ctx context.Context, p string) ([]domain.Link, error) {
 	// open a file and extract urls from it
-	urls := strset.New()
-	errChan := make(chan error, 1)
-	reg := xurls.Strict()
+	type (
+		Result struct {
+			links []domain.Link
+			err   error
+		}
+	)
+	resultChan := make(chan Result, 1)
 	go func() {
 		// open a file
 		fi, err := lgc.fsys.Open(p)
 		if err != nil {
-			errChan <- err
+			resultChan <- Result{err: err}
 			return
 		}
 		defer fi.Close()
-		// read a file per a line
-		scanner := bufio.NewScanner(fi)
-		for scanner.Scan() {
-			// extract urls from a line
-			urls.Add(reg.FindAllString(scanner.Text(), -1)...)
-		}
-		errChan <- scanner.Err()
+		// remove urls which are ignored by directives such as "durl-ignore-line"
+		links, err := lgc.extractURLsWithDirectives(fi, p)
+		resultChan <- Result{links: links, err: err}
 	}()
 	select {
 	case <-ctx.Done():
 		return nil, nil
-	case err := <-errChan:
-		if err != nil {
-			return urls, fmt.Errorf("failed to read %s: %w", p, err)
+	case result := <-resultChan:
+		if result.err != nil {
+			return result.links, fmt.Errorf("failed to read %s: %w", p, result.err)
 		}
-		return urls, nil
+		return result.links, nil
 	}
 }
 
@@ -252,3 +371,47 @@ func (lgc *logic) GetFiles(stdin io.Reader) (*strset.Set, error) {
 	}
 	return files, nil
 }
+
+func (lgc *logic) FindFiles(paths []string) (*strset.Set, error) {
+	// walk directories and return paths of files which match include and exclude patterns
+	files := strset.New()
+	for _, root := range paths {
+		root := root
+		err := lgc.fsys.Walk(root, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				// .git is always skipped even if exclude isn't set
+				if p != root && (info.Name() == ".git" || lgc.isExcludedPath(p)) {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if lgc.isTargetFile(p) {
+				files.Add(p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk %s: %w", root, err)
+		}
+	}
+	return files, nil
+}
+
+func (lgc *logic) ListGitFiles(paths []string) (*strset.Set, error) {
+	// list files which are tracked by git or aren't ignored by .gitignore
+	arr, err := lgc.git.ListFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	files := strset.New()
+	for _, p := range arr {
+		// a file which is tracked but deleted in the working tree is listed
+		if lgc.isTargetFile(p) && lgc.fsys.Exist(p) {
+			files.Add(p)
+		}
+	}
+	return files, nil
+}
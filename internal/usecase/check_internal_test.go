@@ -6,7 +6,11 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -23,8 +27,27 @@ type (
 		buf []byte
 		err error
 	}
+
+	fileInfo struct {
+		os.FileInfo
+		name  string
+		isDir bool
+		size  int64
+	}
 )
 
+func (info fileInfo) Name() string {
+	return info.name
+}
+
+func (info fileInfo) IsDir() bool {
+	return info.isDir
+}
+
+func (info fileInfo) Size() int64 {
+	return info.size
+}
+
 func newFsys(t *testing.T, files map[string]File) *test.Fsys {
 	return test.NewFsys(t, nil).
 		SetFuncOpen(func(p string) (io.ReadCloser, error) {
@@ -42,15 +65,54 @@ func Test_logicCheck(t *testing.T) {
 	data := []struct {
 		title    string
 		mock     domain.Logic
+		paths    []string
 		checkErr func(require.TestingT, interface{}, ...interface{})
 	}{{
-		"normal", test.NewLogic(t, gomic.DoNothing), require.Nil,
+		"normal", test.NewLogic(t, gomic.DoNothing), nil, require.Nil,
 	}, {
 		"failed to get file paths",
-		test.NewLogic(t, gomic.DoNothing).SetReturnGetFiles(nil, fmt.Errorf("failed to get file paths")), require.NotNil,
+		test.NewLogic(t, gomic.DoNothing).SetReturnGetFiles(nil, fmt.Errorf("failed to get file paths")), nil, require.NotNil,
+	}, {
+		"find files",
+		test.NewLogic(t, gomic.DoNothing).
+			SetReturnGetFiles(nil, fmt.Errorf("stdin must not be read")).
+			SetReturnFindFiles(strset.New("foo.txt"), nil),
+		[]string{"."}, require.Nil,
+	}, {
+		"failed to find files",
+		test.NewLogic(t, gomic.DoNothing).SetReturnFindFiles(nil, fmt.Errorf("failed to find files")),
+		[]string{"."}, require.NotNil,
 	}, {
 		"failed to extract urls from files",
 		test.NewLogic(t, gomic.DoNothing).SetReturnExtractURLsFromFiles(nil, fmt.Errorf("failed to extract urls from files")),
+		nil, require.NotNil,
+	}}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			lgc := &logic{
+				logic: tt.mock,
+			}
+			_, err := lgc.Check(bytes.NewBufferString("stdin"), tt.paths)
+			tt.checkErr(t, err)
+		})
+	}
+}
+
+func Test_logicCheckGit(t *testing.T) {
+	data := []struct {
+		title    string
+		mock     domain.Logic
+		checkErr func(require.TestingT, interface{}, ...interface{})
+	}{{
+		"normal",
+		test.NewLogic(t, gomic.DoNothing).
+			SetReturnGetFiles(nil, fmt.Errorf("stdin must not be read")).
+			SetReturnFindFiles(nil, fmt.Errorf("files must not be walked")),
+		require.Nil,
+	}, {
+		"failed to list files",
+		test.NewLogic(t, gomic.DoNothing).SetReturnListGitFiles(nil, fmt.Errorf("not a git repository")),
 		require.NotNil,
 	}}
 	for _, tt := range data {
@@ -58,12 +120,18 @@ func Test_logicCheck(t *testing.T) {
 		t.Run(tt.title, func(t *testing.T) {
 			lgc := &logic{
 				logic: tt.mock,
+				cfg:   domain.Cfg{FileSource: domain.FileSourceGit},
 			}
-			tt.checkErr(t, lgc.Check(bytes.NewBufferString("stdin"), ""))
+			_, err := lgc.Check(bytes.NewBufferString("stdin"), nil)
+			tt.checkErr(t, err)
 		})
 	}
 }
 
+func boolP(b bool) *bool {
+	return &b
+}
+
 func Test_logicIsIgnoredURL(t *testing.T) {
 	data := []struct {
 		url string
@@ -80,9 +148,31 @@ func Test_logicIsIgnoredURL(t *testing.T) {
 		{"https://localhost", true, domain.Cfg{}},
 		{"http://localhost", true, domain.Cfg{}},
 		{"http://localhost:8000", true, domain.Cfg{}},
+		{"https://github.com/our-org/private-repo", true, domain.Cfg{IgnoreURLs: []string{"https://github.com/our-org/private-*"}}},
+		{"https://github.com/our-org/public-repo", false, domain.Cfg{IgnoreURLs: []string{"https://github.com/our-org/private-*"}}},
+		{"https://github.com/our-org/foo?page=2", true, domain.Cfg{IgnoreURLPatterns: []string{`^https://github\.com/our-org/[^/]+\?page=`}}},
+		{"https://github.com/our-org/foo", false, domain.Cfg{IgnoreURLPatterns: []string{`^https://github\.com/our-org/[^/]+\?page=`}}},
+		{"https://ci.internal.corp/job", true, domain.Cfg{IgnoreHosts: []string{"*.internal.corp"}}},
+		{"https://a.b.internal.corp/job", true, domain.Cfg{IgnoreHosts: []string{"*.internal.corp"}}},
+		{"https://internal.corp/job", false, domain.Cfg{IgnoreHosts: []string{"*.internal.corp"}}},
+		{"https://ci.internal.corp.evil.com/job", false, domain.Cfg{IgnoreHosts: []string{"*.internal.corp"}}},
+		{"https://localhost.com:8080/foo", true, domain.Cfg{IgnoreHosts: []string{"localhost.com"}}},
+		{"https://LocalHost.com/foo", true, domain.Cfg{IgnoreHosts: []string{"localhost.COM"}}},
+		{"https://localhost.com:8080/foo", true, domain.Cfg{IgnoreHosts: []string{"localhost.com:8080"}}},
+		{"https://localhost.com:8081/foo", false, domain.Cfg{IgnoreHosts: []string{"localhost.com:8080"}}},
+		{"https://localhost.com/foo", false, domain.Cfg{IgnoreHosts: []string{"localhost.com:8080"}}},
+		{"http://[::1]:8080/", true, domain.Cfg{IgnoreHosts: []string{"::1"}}},
+		{"http://[::1]:8080/", true, domain.Cfg{IgnoreHosts: []string{"[::1]:8080"}}},
+		{"https://ci.internal.corp:8443/job", true, domain.Cfg{IgnoreHosts: []string{"*.internal.corp"}}},
+		{"https://Example.COM:8080", true, domain.Cfg{}},
+		{"https://example.com", false, domain.Cfg{DefaultIgnoreHosts: boolP(false)}},
+		{"https://localhost.com", true, domain.Cfg{DefaultIgnoreHosts: boolP(false), IgnoreHosts: []string{"localhost.com"}}},
+		{"https://example.com", true, domain.Cfg{DefaultIgnoreHosts: boolP(true), IgnoreHosts: []string{"localhost.com"}}},
 	}
 	for _, d := range data {
-		lgc := NewLogic(d.cfg, nil, nil)
+		cfg, err := initIgnores(d.cfg)
+		require.Nil(t, err)
+		lgc := NewLogic(cfg, nil, nil, nil, nil)
 		if d.exp {
 			require.True(t, lgc.IsIgnoredURL(d.url), d.url)
 			continue
@@ -95,28 +185,63 @@ func Test_logicCheckURLs(t *testing.T) {
 	data := []struct {
 		title    string
 		mock     domain.Logic
-		urls     map[string]*strset.Set
+		urls     map[string][]domain.Location
+		cfg      domain.Cfg
 		checkErr func(require.TestingT, interface{}, ...interface{})
+		exp      []domain.Result
 	}{{
 		"normal",
 		test.NewLogic(t, gomic.DoNothing),
-		map[string]*strset.Set{
-			"http://example.com/foo": strset.New("foo.txt"),
+		map[string][]domain.Location{
+			"http://example.com/foo": {{Path: "foo.txt", Line: 1, Column: 1}, {Path: "bar.txt", Line: 3, Column: 5}},
 		},
+		domain.Cfg{},
 		require.Nil,
+		[]domain.Result{{URL: "http://example.com/foo", Locations: []domain.Location{
+			{Path: "bar.txt", Line: 3, Column: 5}, {Path: "foo.txt", Line: 1, Column: 1},
+		}}},
 	}, {
 		"urls is empty",
-		test.NewLogic(t, gomic.DoNothing), nil, require.Nil,
+		test.NewLogic(t, gomic.DoNothing), nil, domain.Cfg{}, require.Nil, nil,
+	}, {
+		"dead url",
+		test.NewLogic(t, gomic.DoNothing).SetReturnCheckURL(domain.Result{
+			StatusCode: 404, ErrorKind: domain.ErrorKindHTTPClientError,
+		}),
+		map[string][]domain.Location{
+			"http://example.com/foo": {{Path: "foo.txt"}},
+		},
+		domain.Cfg{},
+		require.NotNil,
+		[]domain.Result{{
+			URL: "http://example.com/foo", Locations: []domain.Location{{Path: "foo.txt"}},
+			StatusCode: 404, ErrorKind: domain.ErrorKindHTTPClientError,
+		}},
+	}, {
+		"too many urls are dead",
+		test.NewLogic(t, gomic.DoNothing).SetReturnCheckURL(domain.Result{ErrorKind: domain.ErrorKindTimeout}),
+		map[string][]domain.Location{
+			"http://example.com/foo": {{Path: "foo.txt"}},
+			"http://example.com/bar": {{Path: "foo.txt"}},
+		},
+		domain.Cfg{MaxFailedRequestCount: 0},
+		require.NotNil,
+		nil,
 	}}
-	cfg := domain.Cfg{HTTPMethod: "head,get"}
 	for _, tt := range data {
 		tt := tt
 		t.Run(tt.title, func(t *testing.T) {
+			tt.cfg.HTTPMethod = "head,get"
 			lgc := &logic{
-				logic: tt.mock,
-				cfg:   cfg,
+				logic:    tt.mock,
+				cfg:      tt.cfg,
+				reporter: test.NewReporter(t, gomic.DoNothing),
+			}
+			results, err := lgc.CheckURLs(tt.urls)
+			tt.checkErr(t, err)
+			if tt.exp != nil {
+				require.Equal(t, tt.exp, results)
 			}
-			tt.checkErr(t, lgc.CheckURLs(tt.urls))
 		})
 	}
 }
@@ -125,11 +250,21 @@ func Test_logicCheckURLWithMethod(t *testing.T) {
 	data := []struct {
 		title  string
 		client domain.HTTPClient
-		isErr  bool
+		kind   string
 	}{{
 		"failed to request",
 		test.NewHTTPClient(t, gomic.DoNothing).SetReturnDo(nil, fmt.Errorf("failed to request")),
-		true,
+		domain.ErrorKindOther,
+	}, {
+		"timeout",
+		test.NewHTTPClient(t, gomic.DoNothing).SetReturnDo(nil, context.DeadlineExceeded),
+		domain.ErrorKindTimeout,
+	}, {
+		"dns",
+		test.NewHTTPClient(t, gomic.DoNothing).SetReturnDo(nil, &url.Error{
+			Op: "Get", URL: "http://example.com", Err: &net.DNSError{Err: "no such host", Name: "example.com"},
+		}),
+		domain.ErrorKindDNS,
 	}, {
 		"success",
 		test.NewHTTPClient(t, gomic.DoNothing).
@@ -137,7 +272,15 @@ func Test_logicCheckURLWithMethod(t *testing.T) {
 				Body:       ioutil.NopCloser(bytes.NewBufferString("")),
 				StatusCode: 200,
 			}, nil),
-		false,
+		"",
+	}, {
+		"404 error",
+		test.NewHTTPClient(t, gomic.DoNothing).
+			SetReturnDo(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+				StatusCode: 404,
+			}, nil),
+		domain.ErrorKindHTTPClientError,
 	}, {
 		"500 error",
 		test.NewHTTPClient(t, gomic.DoNothing).
@@ -145,7 +288,7 @@ func Test_logicCheckURLWithMethod(t *testing.T) {
 				Body:       ioutil.NopCloser(bytes.NewBufferString("")),
 				StatusCode: 500,
 			}, nil),
-		true,
+		domain.ErrorKindHTTPServerError,
 	}}
 	for _, tt := range data {
 		tt := tt
@@ -153,42 +296,74 @@ func Test_logicCheckURLWithMethod(t *testing.T) {
 			lgc := &logic{
 				client: tt.client,
 			}
-			err := lgc.CheckURLWithMethod(context.Background(), "http://example.com", "get")
-			if tt.isErr {
-				require.NotNil(t, err)
-				return
-			}
-			require.Nil(t, err)
+			result := lgc.CheckURLWithMethod(context.Background(), "http://example.com", "get")
+			require.Equal(t, tt.kind, result.ErrorKind)
+			require.Equal(t, "get", result.Method)
 		})
 	}
 }
 
+func Test_logicCheckURLWithMethodRedirects(t *testing.T) {
+	first, err := http.NewRequest(http.MethodGet, "http://example.com/a", nil)
+	require.Nil(t, err)
+	second, err := http.NewRequest(http.MethodGet, "http://example.com/b", nil)
+	require.Nil(t, err)
+	second.Response = &http.Response{StatusCode: 301, Request: first}
+	third, err := http.NewRequest(http.MethodGet, "http://example.com/c", nil)
+	require.Nil(t, err)
+	third.Response = &http.Response{StatusCode: 302, Request: second}
+	lgc := &logic{
+		client: test.NewHTTPClient(t, gomic.DoNothing).
+			SetReturnDo(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+				StatusCode: 200,
+				Request:    third,
+			}, nil),
+	}
+	result := lgc.CheckURLWithMethod(context.Background(), "http://example.com/a", "get")
+	require.False(t, result.Failed())
+	require.Equal(t, []domain.Redirect{
+		{StatusCode: 301, URL: "http://example.com/b"},
+		{StatusCode: 302, URL: "http://example.com/c"},
+	}, result.Redirects)
+}
+
 func Test_logicCheckURL(t *testing.T) {
 	data := []struct {
-		title    string
-		method   string
-		mock     domain.Logic
-		checkErr func(require.TestingT, interface{}, ...interface{})
+		title  string
+		method string
+		mock   domain.Logic
+		failed bool
 	}{{
 		"get", "get",
 		test.NewLogic(t, gomic.DoNothing),
-		require.Nil,
+		false,
 	}, {
 		"head", "head",
 		test.NewLogic(t, gomic.DoNothing),
-		require.Nil,
+		false,
 	}, {
 		"head,get", "head,get",
 		test.NewLogic(t, gomic.DoNothing),
-		require.Nil,
+		false,
 	}, {
 		"empty", "",
 		test.NewLogic(t, gomic.DoNothing),
-		require.Nil,
+		false,
+	}, {
+		"head,get fallback", "head,get",
+		test.NewLogic(t, gomic.DoNothing).
+			SetFuncCheckURLWithMethod(func(ctx context.Context, u, method string) domain.Result {
+				if method == http.MethodHead {
+					return domain.Result{Method: method, ErrorKind: domain.ErrorKindHTTPClientError}
+				}
+				return domain.Result{Method: method}
+			}),
+		false,
 	}, {
 		"invalid method", "invalid method",
 		test.NewLogic(t, gomic.DoNothing),
-		require.NotNil,
+		true,
 	}}
 	client := &http.Client{
 		Timeout: domain.DefaultTimeout,
@@ -201,7 +376,7 @@ func Test_logicCheckURL(t *testing.T) {
 				cfg:    domain.Cfg{HTTPMethod: tt.method},
 				client: client,
 			}
-			tt.checkErr(t, lgc.CheckURL(context.Background(), "http://example.com"))
+			require.Equal(t, tt.failed, lgc.CheckURL(context.Background(), "http://example.com").Failed())
 		})
 	}
 }
@@ -211,35 +386,45 @@ func Test_logicExtractURLsFromFiles(t *testing.T) {
 		title    string
 		files    map[string]File
 		checkErr func(require.TestingT, interface{}, ...interface{})
-		set      map[string]*strset.Set
+		set      map[string][]domain.Location
 	}{{
 		"no url", map[string]File{
 			"foo.txt": {[]byte(`foo`), nil},
-		}, require.Nil, map[string]*strset.Set{},
+		}, require.Nil, map[string][]domain.Location{},
 	}, {
 		"normal", map[string]File{
 			"foo.txt": {[]byte(`foo`), nil},
 			"bar.txt": {[]byte(`http://example.com`), nil},
-		}, require.Nil, map[string]*strset.Set{
-			"http://example.com": strset.New("bar.txt"),
+		}, require.Nil, map[string][]domain.Location{
+			"http://example.com": {{Path: "bar.txt", Line: 1, Column: 1}},
 		},
 	}, {
 		"error", map[string]File{
 			"bar.txt": {[]byte(`http://example.com`), nil},
 			"foo.txt": {nil, fmt.Errorf("failed to read a file")},
-		}, require.Nil, map[string]*strset.Set{
-			"http://example.com": strset.New("bar.txt"),
+		}, require.Nil, map[string][]domain.Location{
+			"http://example.com": {{Path: "bar.txt", Line: 1, Column: 1}},
+		},
+	}, {
+		"too large file", map[string]File{
+			"bar.txt": {[]byte(`http://example.com`), nil},
+			"foo.txt": {[]byte(`http://example.com/foo` + strings.Repeat("X", 100)), nil},
+		}, require.Nil, map[string][]domain.Location{
+			"http://example.com": {{Path: "bar.txt", Line: 1, Column: 1}},
 		},
 	}}
 	for _, tt := range data {
 		tt := tt
 		t.Run(tt.title, func(t *testing.T) {
-			fsys := newFsys(t, tt.files)
+			fsys := newFsys(t, tt.files).
+				SetFuncStat(func(p string) (os.FileInfo, error) {
+					return fileInfo{name: p, size: int64(len(tt.files[p].buf))}, nil
+				})
 			files := strset.New()
 			for k := range tt.files {
 				files.Add(k)
 			}
-			lgc := NewLogic(domain.Cfg{}, fsys, nil)
+			lgc := NewLogic(domain.Cfg{MaxFileSize: 100}, fsys, nil, nil, nil)
 			set, err := lgc.ExtractURLsFromFiles(files)
 			tt.checkErr(t, err)
 			if err == nil {
@@ -255,17 +440,32 @@ func Test_logicExtractURLsFromFile(t *testing.T) {
 		buf      []byte
 		err      error
 		checkErr func(require.TestingT, interface{}, ...interface{})
-		set      *strset.Set
+		links    []domain.Link
 		p        string
 	}{{
 		"no url", []byte(`foo
-bar`), nil, require.Nil, strset.New(), "foo.txt",
+bar`), nil, require.Nil, []domain.Link{}, "foo.txt",
+	}, {
+		"normal", []byte(`http://example.com`), nil, require.Nil, []domain.Link{
+			{URL: "http://example.com", Location: domain.Location{Path: "foo.txt", Line: 1, Column: 1}},
+		}, "foo.txt",
 	}, {
-		"normal", []byte(`http://example.com`), nil, require.Nil, strset.New("http://example.com"), "foo.txt",
+		"multiple urls", []byte(`foo
+see http://example.com/foo and http://example.com/bar
+日本語 http://example.com/foo`), nil, require.Nil, []domain.Link{
+			{URL: "http://example.com/foo", Location: domain.Location{Path: "foo.txt", Line: 2, Column: 5}},
+			{URL: "http://example.com/bar", Location: domain.Location{Path: "foo.txt", Line: 2, Column: 32}},
+			{URL: "http://example.com/foo", Location: domain.Location{Path: "foo.txt", Line: 3, Column: 5}},
+		}, "foo.txt",
 	}, {
 		"error", nil, fmt.Errorf("failed to read a file"), require.NotNil, nil, "foo.txt",
 	}, {
-		"too long", []byte(strings.Repeat("X", 65536)), nil, require.NotNil, nil, "foo.txt",
+		"too long", []byte(strings.Repeat("X", 65536)), nil, require.Nil, []domain.Link{}, "foo.txt",
+	}, {
+		"url in a long line", []byte(strings.Repeat("X ", 50000) + "http://example.com\nhttp://example.com/foo"), nil, require.Nil, []domain.Link{
+			{URL: "http://example.com", Location: domain.Location{Path: "foo.txt", Line: 1, Column: 100001}},
+			{URL: "http://example.com/foo", Location: domain.Location{Path: "foo.txt", Line: 2, Column: 1}},
+		}, "foo.txt",
 	}}
 	for _, tt := range data {
 		tt := tt
@@ -276,13 +476,11 @@ bar`), nil, require.Nil, strset.New(), "foo.txt",
 			}
 			fsys := test.NewFsys(t, nil).
 				SetReturnOpen(rc, tt.err)
-			lgc := NewLogic(domain.Cfg{}, fsys, nil)
-			set, err := lgc.ExtractURLsFromFile(context.Background(), tt.p)
+			lgc := NewLogic(domain.Cfg{}, fsys, nil, nil, nil)
+			links, err := lgc.ExtractURLsFromFile(context.Background(), tt.p)
 			tt.checkErr(t, err)
 			if err == nil {
-				if !set.IsEqual(tt.set) {
-					t.Fatalf("set = %v, wanted %v", set, tt.set)
-				}
+				require.Equal(t, tt.links, links)
 			}
 		})
 	}
@@ -303,7 +501,7 @@ bar`, require.Nil, strset.New("foo", "bar"),
 bar
 `, require.Nil, strset.New("foo", "bar"),
 	}}
-	lgc := NewLogic(domain.Cfg{}, nil, nil)
+	lgc := NewLogic(domain.Cfg{}, nil, nil, nil, nil)
 	for _, tt := range data {
 		tt := tt
 		t.Run(tt.title, func(t *testing.T) {
@@ -317,3 +515,114 @@ bar
 		})
 	}
 }
+
+func Test_logicFindFiles(t *testing.T) {
+	type walkedFile struct {
+		path  string
+		isDir bool
+	}
+	data := []struct {
+		title    string
+		cfg      domain.Cfg
+		walked   []walkedFile
+		walkErr  error
+		checkErr func(require.TestingT, interface{}, ...interface{})
+		exp      *strset.Set
+	}{{
+		"no pattern", domain.Cfg{Exclude: []string{}}, []walkedFile{
+			{".", true}, {"README.md", false}, {"docs", true}, {"docs/foo.md", false},
+		}, nil, require.Nil, strset.New("README.md", "docs/foo.md"),
+	}, {
+		".git is always skipped", domain.Cfg{Exclude: []string{}}, []walkedFile{
+			{".", true}, {".git", true}, {".git/hooks/fsmonitor-watchman.sample", false},
+			{"README.md", false}, {"docs", true}, {"docs/.git", true}, {"docs/.git/HEAD", false},
+		}, nil, require.Nil, strset.New("README.md"),
+	}, {
+		"include and exclude", domain.Cfg{
+			Include: []string{"**/*.md"},
+			Exclude: []string{"node_modules", "docs/draft/**"},
+		}, []walkedFile{
+			{".", true}, {"README.md", false}, {"main.go", false},
+			{"node_modules", true}, {"node_modules/foo/README.md", false},
+			{"docs", true}, {"docs/foo.md", false}, {"docs/draft", true}, {"docs/draft/bar.md", false},
+		}, nil, require.Nil, strset.New("README.md", "docs/foo.md"),
+	}, {
+		"failed to walk", domain.Cfg{}, nil, fmt.Errorf("permission denied"), require.NotNil, nil,
+	}}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			reader := &cfgReader{}
+			cfg, err := reader.InitCfg(tt.cfg)
+			require.Nil(t, err)
+			fsys := test.NewFsys(t, nil).
+				SetFuncWalk(func(root string, fn filepath.WalkFunc) error {
+					if tt.walkErr != nil {
+						return fn(root, nil, tt.walkErr)
+					}
+					skipped := []string{}
+				L:
+					for _, f := range tt.walked {
+						for _, s := range skipped {
+							if strings.HasPrefix(f.path, s+"/") {
+								continue L
+							}
+						}
+						if err := fn(f.path, fileInfo{name: filepath.Base(f.path), isDir: f.isDir}, nil); err != nil {
+							if err == filepath.SkipDir {
+								skipped = append(skipped, f.path)
+								continue
+							}
+							return err
+						}
+					}
+					return nil
+				})
+			lgc := NewLogic(cfg, fsys, nil, nil, nil)
+			files, err := lgc.FindFiles([]string{"."})
+			tt.checkErr(t, err)
+			if err == nil {
+				if !files.IsEqual(tt.exp) {
+					t.Fatalf("files = %v, wanted %v", files, tt.exp)
+				}
+			}
+		})
+	}
+}
+
+func Test_logicListGitFiles(t *testing.T) {
+	data := []struct {
+		title    string
+		cfg      domain.Cfg
+		git      domain.Git
+		checkErr func(require.TestingT, interface{}, ...interface{})
+		exp      *strset.Set
+	}{{
+		"normal", domain.Cfg{Include: []string{"*.md"}},
+		test.NewGit(t, nil).SetReturnListFiles([]string{"README.md", "main.go", "docs/deleted.md"}, nil),
+		require.Nil, strset.New("README.md"),
+	}, {
+		"failed to run git", domain.Cfg{},
+		test.NewGit(t, nil).SetReturnListFiles(nil, fmt.Errorf("not a git repository")),
+		require.NotNil, nil,
+	}}
+	fsys := test.NewFsys(t, nil).SetFuncExist(func(p string) bool {
+		return p != "docs/deleted.md"
+	})
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			reader := &cfgReader{}
+			cfg, err := reader.InitCfg(tt.cfg)
+			require.Nil(t, err)
+			lgc := NewLogic(cfg, fsys, nil, tt.git, nil)
+			files, err := lgc.ListGitFiles(nil)
+			tt.checkErr(t, err)
+			if err == nil {
+				if !files.IsEqual(tt.exp) {
+					t.Fatalf("files = %v, wanted %v", files, tt.exp)
+				}
+			}
+		})
+	}
+}
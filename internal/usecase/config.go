@@ -65,11 +65,33 @@ func (reader *cfgReader) InitCfg(cfg domain.Cfg) (domain.Cfg, error) {
 	if _, ok := methods[cfg.HTTPMethod]; !ok {
 		return cfg, fmt.Errorf(`invalid http_method_type: %s`, cfg.HTTPMethod)
 	}
+	if cfg.FileSource != "" && cfg.FileSource != domain.FileSourceGit {
+		return cfg, fmt.Errorf(`invalid file_source: %s`, cfg.FileSource)
+	}
 	if cfg.HTTPRequestTimeout == 0 {
 		cfg.HTTPRequestTimeout = domain.DefaultTimeout
 	}
 	if cfg.MaxRequestCount == 0 {
 		cfg.MaxRequestCount = domain.DefaultMaxRequestCount
 	}
+	for _, initCfg := range []func(domain.Cfg) (domain.Cfg, error){
+		initRetry, initHosts, initRedirects, initCache, initStatusCodes, initIgnores, initIPNets,
+	} {
+		c, err := initCfg(cfg)
+		if err != nil {
+			return c, err
+		}
+		cfg = c
+	}
+	includes, err := compileGlobs(cfg.Include)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid include: %w", err)
+	}
+	cfg.IncludePatterns = includes
+	excludes, err := compileGlobs(cfg.Exclude)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid exclude: %w", err)
+	}
+	cfg.ExcludePatterns = excludes
 	return cfg, nil
 }
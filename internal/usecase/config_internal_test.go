@@ -58,6 +58,57 @@ func Test_cfgReaderInitCfg(t *testing.T) {
 	require.Equal(t, "head,get", cfg.HTTPMethod)
 	require.Equal(t, domain.DefaultTimeout, cfg.HTTPRequestTimeout)
 	require.Equal(t, domain.DefaultMaxRequestCount, cfg.MaxRequestCount)
+	require.Equal(t, domain.DefaultRetryBackoff, cfg.RetryBackoff)
+	require.Equal(t, domain.DefaultRetryMaxDelay, cfg.RetryMaxDelay)
+	require.Equal(t, domain.DefaultCacheFile, cfg.CacheFile)
+	require.Equal(t, domain.DefaultCacheTTL, cfg.CacheTTL)
+	require.Equal(t, domain.DefaultCacheFailureTTL, cfg.CacheFailureTTL)
+
+	_, err = reader.InitCfg(domain.Cfg{CacheFailureTTL: -1})
+	require.NotNil(t, err)
+
+	_, err = reader.InitCfg(domain.Cfg{RetryCount: -1})
+	require.NotNil(t, err)
+
+	cfg, err = reader.InitCfg(domain.Cfg{Hosts: map[string]domain.HostCfg{"GitHub.com": {MaxRequestCount: 1}}})
+	require.Nil(t, err)
+	require.Equal(t, map[string]domain.HostCfg{"github.com": {MaxRequestCount: 1}}, cfg.Hosts)
+
+	_, err = reader.InitCfg(domain.Cfg{Hosts: map[string]domain.HostCfg{"github.com": {RequestsPerSecond: -1}}})
+	require.NotNil(t, err)
+
+	cfg, err = reader.InitCfg(domain.Cfg{Include: []string{"*.md"}, Exclude: []string{"vendor"}})
+	require.Nil(t, err)
+	require.Len(t, cfg.IncludePatterns, 1)
+	require.Len(t, cfg.ExcludePatterns, 1)
+
+	_, err = reader.InitCfg(domain.Cfg{Exclude: []string{"[vendor"}})
+	require.NotNil(t, err)
+
+	cfg, err = reader.InitCfg(domain.Cfg{
+		IgnoreURLs:        []string{"https://github.com/suzuki-shunsuke/durl", "https://github.com/our-org/*"},
+		IgnoreURLPatterns: []string{`^https://github\.com/our-org/`},
+		IgnoreHosts:       []string{"*.internal.corp", "example.com"},
+	})
+	require.Nil(t, err)
+	require.Len(t, cfg.IgnoreURLRegexps, 2)
+	require.Len(t, cfg.IgnoreHostRegexps, len(domain.DefaultIgnoreHosts)+2)
+
+	_, err = reader.InitCfg(domain.Cfg{IgnoreHosts: []string{":8080"}})
+	require.NotNil(t, err)
+
+	_, err = reader.InitCfg(domain.Cfg{IgnoreURLPatterns: []string{"https://github.com/(foo"}})
+	require.NotNil(t, err)
+
+	cfg, err = reader.InitCfg(domain.Cfg{IgnoreCIDRs: []string{"100.64.0.0/10"}, IgnorePrivateIPs: true})
+	require.Nil(t, err)
+	require.Len(t, cfg.IgnoreIPNets, len(privateCIDRs)+1)
+
+	_, err = reader.InitCfg(domain.Cfg{IgnoreCIDRs: []string{"10.0.0.0/40"}})
+	require.NotNil(t, err)
+
+	_, err = reader.InitCfg(domain.Cfg{FileSource: "svn"})
+	require.NotNil(t, err)
 }
 
 func Test_cfgReaderReadCfg(t *testing.T) {
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/scylladb/go-set/strset"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// hunkHeader matches a hunk header of the unified diff such as "@@ -1,2 +3,4 @@".
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`) //nolint:gochecknoglobals
+
+func (lgc *logic) getURLsFromDiff(paths []string) (map[string][]domain.Location, error) {
+	diff, err := lgc.git.Diff(lgc.cfg.DiffBase, paths)
+	if err != nil {
+		return nil, err
+	}
+	return lgc.logic.ExtractURLsFromDiff(bytes.NewReader(diff))
+}
+
+// ExtractURLsFromDiff extracts urls in lines which are added or modified in the unified diff.
+// Urls are extracted from the whole changed files in the same way as files which aren't diffed,
+// so that links in Markdown and HTML, relative links and directives are handled,
+// and then urls which aren't in the added lines are removed.
+func (lgc *logic) ExtractURLsFromDiff(diff io.Reader) (map[string][]domain.Location, error) {
+	addedLines, err := lgc.parseAddedLines(diff)
+	if err != nil {
+		return nil, err
+	}
+	files := strset.New()
+	for p := range addedLines {
+		files.Add(p)
+	}
+	urls, err := lgc.logic.ExtractURLsFromFiles(files)
+	if err != nil {
+		return nil, err
+	}
+	for u, locs := range urls {
+		filtered := make([]domain.Location, 0, len(locs))
+		for _, loc := range locs {
+			if addedLines[loc.Path].contains(loc.Line) {
+				filtered = append(filtered, loc)
+			}
+		}
+		if len(filtered) == 0 {
+			delete(urls, u)
+			continue
+		}
+		urls[u] = filtered
+	}
+	return urls, nil
+}
+
+// parseAddedLines returns ranges of lines which are added or modified in the unified diff per file path.
+// Files which are deleted or excluded aren't included.
+func (lgc *logic) parseAddedLines(diff io.Reader) (map[string]lineRanges, error) {
+	// file path -> added lines
+	addedLines := map[string]lineRanges{}
+	reader := bufio.NewReader(diff)
+	// the file path after the change. If the file is deleted or excluded, p is empty.
+	p := ""
+	// the number of remaining lines in the current hunk
+	oldLines, newLines := 0, 0
+	// the line number of the next line in the file after the change
+	lineNum := 0
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read the diff: %w", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case oldLines > 0 || newLines > 0:
+			// a line in a hunk
+			switch {
+			case strings.HasPrefix(line, "+"):
+				newLines--
+				if p != "" {
+					addedLines[p] = addedLines[p].add(lineNum)
+				}
+				lineNum++
+			case strings.HasPrefix(line, "-"):
+				oldLines--
+			case strings.HasPrefix(line, `\`):
+				// "\ No newline at end of file"
+			default:
+				oldLines--
+				newLines--
+				lineNum++
+			}
+		case strings.HasPrefix(line, "+++ "):
+			p = lgc.parseDiffPath(strings.TrimPrefix(line, "+++ "))
+		case strings.HasPrefix(line, "@@ "):
+			m := hunkHeader.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("invalid hunk header: %s", line)
+			}
+			oldLines = parseHunkLength(m[1])
+			newLines = parseHunkLength(m[3])
+			lineNum, _ = strconv.Atoi(m[2])
+		}
+		if err == io.EOF {
+			return addedLines, nil
+		}
+	}
+}
+
+// parseDiffPath returns the file path from the "+++ " line of the unified diff.
+// If the file is deleted or excluded, an empty string is returned.
+func (lgc *logic) parseDiffPath(s string) string {
+	// git adds a TAB after the path which includes a space
+	s = strings.TrimSuffix(s, "\t")
+	if s == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(s, `"`) {
+		if a, err := strconv.Unquote(s); err == nil {
+			s = a
+		}
+	}
+	s = strings.TrimPrefix(s, "b/")
+	if !lgc.isTargetFile(s) {
+		return ""
+	}
+	return s
+}
+
+func parseHunkLength(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/gomic/gomic"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+	"github.com/suzuki-shunsuke/durl/internal/test"
+)
+
+func Test_logicExtractURLsFromDiff(t *testing.T) {
+	data := []struct {
+		title    string
+		diff     string
+		cfg      domain.Cfg
+		checkErr func(require.TestingT, interface{}, ...interface{})
+		exp      map[string][]domain.Location
+	}{{
+		"empty", "", domain.Cfg{}, require.Nil, nil,
+	}, {
+		"normal", `diff --git a/README.md b/README.md
+index 1111111..2222222 100644
+--- a/README.md
++++ b/README.md
+@@ -3 +3,2 @@ foo
+-see https://example.com/old
++see [new](https://example.com/new)
++++ https://example.com/plus
+@@ -10,0 +12 @@ bar
++https://example.com/new
+diff --git a/old.md b/old.md
+deleted file mode 100644
+--- a/old.md
++++ /dev/null
+@@ -1 +0,0 @@
+-https://example.com/deleted
+diff --git a/main.go b/main.go
+--- a/main.go
++++ b/main.go
+@@ -1,0 +1 @@
++// https://example.com/go
+diff --git "a/\343\201\202.md" "b/\343\201\202.md"
+--- "a/\343\201\202.md"
++++ "b/\343\201\202.md"
+@@ -1 +1 @@
+-foo
++https://example.com/quoted
+\ No newline at end of file
+diff --git a/sp ace.md b/sp ace.md
+--- a/sp ace.md	
++++ b/sp ace.md	
+@@ -1,0 +2 @@
++https://example.com/space
+`, domain.Cfg{Include: []string{"*.md"}}, require.Nil, map[string][]domain.Location{
+			"https://example.com/new": {
+				{Path: "README.md", Line: 3, Column: 11},
+				{Path: "README.md", Line: 12, Column: 1},
+			},
+			"https://example.com/plus":   {{Path: "README.md", Line: 4, Column: 4}},
+			"https://example.com/quoted": {{Path: "あ.md", Line: 1, Column: 1}},
+			"https://example.com/space":  {{Path: "sp ace.md", Line: 2, Column: 1}},
+		},
+	}, {
+		"invalid hunk header", `--- a/README.md
++++ b/README.md
+@@ foo @@
+`, domain.Cfg{}, require.NotNil, nil,
+	}}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			reader := &cfgReader{}
+			cfg, err := reader.InitCfg(tt.cfg)
+			require.Nil(t, err)
+			// urls are extracted from the whole files and urls in unchanged lines are removed
+			fsys := newFsys(t, map[string]File{
+				"README.md": {buf: []byte("# README\n\nsee [new](https://example.com/new)\n++ https://example.com/plus\n" +
+					"https://example.com/unchanged\n" + strings.Repeat("\n", 6) + "https://example.com/new\n")},
+				"main.go":   {buf: []byte("// https://example.com/go\n")},
+				"あ.md":      {buf: []byte("https://example.com/quoted")},
+				"sp ace.md": {buf: []byte("# space\nhttps://example.com/space\n")},
+			})
+			lgc := NewLogic(cfg, fsys, nil, nil, nil)
+			urls, err := lgc.ExtractURLsFromDiff(bytes.NewBufferString(tt.diff))
+			tt.checkErr(t, err)
+			if err == nil {
+				require.Equal(t, tt.exp, urls)
+			}
+		})
+	}
+}
+
+func Test_logicCheckDiff(t *testing.T) {
+	data := []struct {
+		title    string
+		git      domain.Git
+		mock     domain.Logic
+		checkErr func(require.TestingT, interface{}, ...interface{})
+	}{{
+		"normal",
+		test.NewGit(t, nil).SetReturnDiff([]byte(""), nil),
+		test.NewLogic(t, gomic.DoNothing).
+			SetReturnGetFiles(nil, fmt.Errorf("stdin must not be read")).
+			SetReturnExtractURLsFromFiles(nil, fmt.Errorf("files must not be read")),
+		require.Nil,
+	}, {
+		"failed to get the diff",
+		test.NewGit(t, nil).SetReturnDiff(nil, fmt.Errorf("unknown revision")),
+		test.NewLogic(t, gomic.DoNothing),
+		require.NotNil,
+	}, {
+		"failed to extract urls from the diff",
+		test.NewGit(t, nil).SetReturnDiff([]byte(""), nil),
+		test.NewLogic(t, gomic.DoNothing).SetReturnExtractURLsFromDiff(nil, fmt.Errorf("invalid hunk header")),
+		require.NotNil,
+	}}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			lgc := &logic{
+				logic: tt.mock,
+				cfg:   domain.Cfg{DiffBase: "origin/master"},
+				git:   tt.git,
+			}
+			_, err := lgc.Check(bytes.NewBufferString("stdin"), nil)
+			tt.checkErr(t, err)
+		})
+	}
+}
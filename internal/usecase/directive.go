@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"io"
+	"io/ioutil"
+	"regexp"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+const (
+	// directiveIgnoreLine ignores urls in the line of the directive.
+	directiveIgnoreLine = "ignore-line"
+	// directiveIgnoreNextLine ignores urls in the next line of the directive.
+	directiveIgnoreNextLine = "ignore-next-line"
+	// directiveDisable ignores urls from the line of the directive to the line of durl-enable.
+	directiveDisable = "disable"
+	// directiveEnable ends durl-disable.
+	directiveEnable = "enable"
+)
+
+// directiveRegexp matches a directive in a comment such as "<!-- durl-ignore-line -->", "# durl-disable" and "// durl-enable".
+// The start of the comment must be at the start of the line or follow a whitespace,
+// so that a directive in a text such as "`# durl-disable`" in Markdown isn't matched.
+var directiveRegexp = regexp.MustCompile(`(?:^|\s)(?:<!--|#|//|/\*|--|;)\s*durl-(ignore-next-line|ignore-line|disable|enable)\b`) //nolint:gochecknoglobals
+
+type (
+	// lineRange is a range of lines from start to end (inclusive). If end is 0, the range continues to the end of the file.
+	lineRange struct {
+		start int
+		end   int
+	}
+
+	lineRanges []lineRange
+)
+
+// add adds the line to the ranges. The line must be larger than lines in the ranges.
+// If the line follows the last range, the range is extended.
+func (ranges lineRanges) add(line int) lineRanges {
+	if n := len(ranges); n != 0 && ranges[n-1].end == line-1 {
+		ranges[n-1].end = line
+		return ranges
+	}
+	return append(ranges, lineRange{start: line, end: line})
+}
+
+func (ranges lineRanges) contains(line int) bool {
+	for _, r := range ranges {
+		if line >= r.start && (r.end == 0 || line <= r.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDirectives returns ranges of lines which are ignored by directives.
+func parseDirectives(r io.Reader) (lineRanges, error) {
+	ranges := lineRanges{}
+	// the start line of durl-disable. If durl-disable isn't active, disabled is 0
+	disabled := 0
+	err := scanLines(r, func(text string, line, col int) {
+		for _, match := range directiveRegexp.FindAllStringSubmatch(text, -1) {
+			switch match[1] {
+			case directiveIgnoreLine:
+				ranges = append(ranges, lineRange{start: line, end: line})
+			case directiveIgnoreNextLine:
+				ranges = append(ranges, lineRange{start: line + 1, end: line + 1})
+			case directiveDisable:
+				if disabled == 0 {
+					disabled = line
+				}
+			case directiveEnable:
+				if disabled != 0 {
+					ranges = append(ranges, lineRange{start: disabled, end: line})
+					disabled = 0
+				}
+			}
+		}
+	})
+	if disabled != 0 {
+		ranges = append(ranges, lineRange{start: disabled})
+	}
+	return ranges, err
+}
+
+// extractURLsWithDirectives extracts urls from the file and removes urls in lines which are ignored by directives.
+// Directives are parsed from the same read of the file as the extractor, so the file is read only once.
+func (lgc *logic) extractURLsWithDirectives(r io.Reader, p string) ([]domain.Link, error) {
+	type parsed struct {
+		ranges lineRanges
+		err    error
+	}
+	pr, pw := io.Pipe()
+	parsedChan := make(chan parsed, 1)
+	go func() {
+		ranges, err := parseDirectives(pr)
+		// drain the pipe so that the extractor isn't blocked even if parsing directives fails
+		_, _ = io.Copy(ioutil.Discard, pr)
+		parsedChan <- parsed{ranges: ranges, err: err}
+	}()
+	tee := io.TeeReader(r, pw)
+	links, err := lgc.extractURLs(tee, p)
+	if err == nil {
+		// directives are parsed to the end of the file even if the extractor doesn't read it
+		_, err = io.Copy(ioutil.Discard, tee)
+	}
+	// if err is nil, the parser reads EOF
+	pw.CloseWithError(err)
+	directives := <-parsedChan
+	if err != nil {
+		return nil, err
+	}
+	if directives.err != nil {
+		return nil, directives.err
+	}
+	return filterIgnoredLinks(links, directives.ranges), nil
+}
+
+// filterIgnoredLinks removes links in lines which are ignored by directives.
+func filterIgnoredLinks(links []domain.Link, ranges lineRanges) []domain.Link {
+	if len(ranges) == 0 {
+		return links
+	}
+	filtered := make([]domain.Link, 0, len(links))
+	for _, link := range links {
+		if !ranges.contains(link.Location.Line) {
+			filtered = append(filtered, link)
+		}
+	}
+	return filtered
+}
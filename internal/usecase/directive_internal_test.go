@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/suzuki-shunsuke/durl/internal/test"
+)
+
+func Test_parseDirectives(t *testing.T) {
+	data := []struct {
+		title string
+		text  string
+		exp   lineRanges
+	}{
+		{"no directive", "https://github.com\n", lineRanges{}},
+		{"ignore-line", "foo\nhttps://github.com <!-- durl-ignore-line -->\n", lineRanges{{start: 2, end: 2}}},
+		{"ignore-next-line", "# durl-ignore-next-line\nhttps://github.com\n", lineRanges{{start: 2, end: 2}}},
+		{
+			"disable and enable", "// durl-disable\nfoo\n/* durl-enable */\n-- durl-disable\n",
+			lineRanges{{start: 1, end: 3}, {start: 4}},
+		},
+		{"not comment", "`durl-ignore-line` is a directive\n", lineRanges{}},
+		{"comment after code", "fmt.Println(\"https://github.com\") // durl-ignore-line\n", lineRanges{{start: 1, end: 1}}},
+		{"inline code", "such as `# durl-ignore-next-line` in YAML\nhttps://github.com\n", lineRanges{}},
+		{"enable without disable", "; durl-enable\n", lineRanges{}},
+	}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			ranges, err := parseDirectives(strings.NewReader(tt.text))
+			require.Nil(t, err)
+			require.Equal(t, tt.exp, ranges)
+		})
+	}
+}
+
+func Test_logicExtractURLsFromFileDirectives(t *testing.T) {
+	files := newFsys(t, map[string]File{
+		"CHANGELOG.md": {buf: []byte(`# Changelog
+
+https://github.com/suzuki-shunsuke/durl/pull/1 <!-- durl-ignore-line -->
+<!-- durl-ignore-next-line -->
+https://github.com/suzuki-shunsuke/durl/pull/2
+https://github.com/suzuki-shunsuke/durl/pull/3
+
+<!-- durl-disable -->
+https://github.com/suzuki-shunsuke/durl/pull/4
+<!-- durl-enable -->
+https://github.com/suzuki-shunsuke/durl/pull/5
+`)},
+	})
+	// directives are parsed without opening the file again
+	opened := 0
+	lgc := &logic{
+		fsys: test.NewFsys(t, nil).SetFuncOpen(func(p string) (io.ReadCloser, error) {
+			opened++
+			return files.Open(p)
+		}),
+	}
+	links, err := lgc.ExtractURLsFromFile(context.Background(), "CHANGELOG.md")
+	require.Nil(t, err)
+	require.Equal(t, 1, opened)
+	urls := make([]string, len(links))
+	for i, link := range links {
+		urls[i] = link.URL
+	}
+	require.Equal(t, []string{
+		"https://github.com/suzuki-shunsuke/durl/pull/3",
+		"https://github.com/suzuki-shunsuke/durl/pull/5",
+	}, urls)
+}
+
+func Test_lineRangesAdd(t *testing.T) {
+	ranges := lineRanges{}
+	for _, line := range []int{3, 4, 5, 8, 10, 11} {
+		ranges = ranges.add(line)
+	}
+	require.Equal(t, lineRanges{{start: 3, end: 5}, {start: 8, end: 8}, {start: 10, end: 11}}, ranges)
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"mvdan.cc/xurls/v2"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// markdownExts are extensions of Markdown files.
+var markdownExts = map[string]struct{}{ //nolint:gochecknoglobals
+	".md": {}, ".markdown": {}, ".mdown": {}, ".mkd": {}, ".mkdn": {},
+}
+
+// htmlExts are extensions of HTML files.
+var htmlExts = map[string]struct{}{ //nolint:gochecknoglobals
+	".html": {}, ".htm": {}, ".xhtml": {},
+}
+
+// extractURLs extracts urls from the file with the extractor selected by the file extension.
+func (lgc *logic) extractURLs(r io.Reader, p string) ([]domain.Link, error) {
+	ext := strings.ToLower(filepath.Ext(p))
+	if _, ok := markdownExts[ext]; ok {
+		return extractURLsFromMarkdown(r, p, lgc.cfg.MarkdownSkipCode)
+	}
+	if _, ok := htmlExts[ext]; ok {
+		return extractURLsFromHTML(r, p)
+	}
+	return extractURLsFromText(r, p)
+}
+
+// extractURLsFromText extracts urls from the plain text line by line.
+func extractURLsFromText(r io.Reader, p string) ([]domain.Link, error) {
+	links := []domain.Link{}
+	reg := xurls.Strict()
+	err := scanLines(r, func(text string, line, col int) {
+		// extract urls from a line
+		for _, idx := range reg.FindAllStringIndex(text, -1) {
+			links = append(links, domain.Link{
+				URL: text[idx[0]:idx[1]],
+				Location: domain.Location{
+					Path:   p,
+					Line:   line,
+					Column: col + utf8.RuneCountInString(text[:idx[0]]),
+				},
+			})
+		}
+	})
+	return links, err
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// diffContextLines is the number of context lines of the unified diff.
+const diffContextLines = 3
+
+// replacement replaces the url at the position in a file.
+type replacement struct {
+	line   int
+	column int
+	old    string
+	new    string
+}
+
+// Fix rewrites urls which are permanently redirected to the suggested urls in files.
+// If dryRun is true, files aren't changed and the unified diff is output to w.
+// Otherwise, paths of the rewritten files are output to w.
+func (lgc *logic) Fix(results []domain.Result, dryRun bool, w io.Writer) error {
+	// file path -> replacements
+	replacements := map[string][]replacement{}
+	for _, result := range results {
+		if result.SuggestedURL == "" || (result.Failed() && result.ErrorKind != domain.ErrorKindRedirect) {
+			continue
+		}
+		for _, loc := range result.Locations {
+			replacements[loc.Path] = append(replacements[loc.Path], replacement{
+				line:   loc.Line,
+				column: loc.Column,
+				old:    result.URL,
+				new:    result.SuggestedURL,
+			})
+		}
+	}
+	paths := make([]string, 0, len(replacements))
+	for p := range replacements {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if err := lgc.fixFile(p, replacements[p], dryRun, w); err != nil {
+			return fmt.Errorf("failed to fix %s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+func (lgc *logic) fixFile(p string, replacements []replacement, dryRun bool, w io.Writer) error {
+	f, err := lgc.fsys.Open(p)
+	if err != nil {
+		return err
+	}
+	b, err := ioutil.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return err
+	}
+	lines := strings.SplitAfter(string(b), "\n")
+	newLines := make([]string, len(lines))
+	copy(newLines, lines)
+	// replace urls from the end of each line so that the positions of the other urls aren't changed
+	sort.Slice(replacements, func(i, j int) bool {
+		a, b := replacements[i], replacements[j]
+		if a.line != b.line {
+			return a.line < b.line
+		}
+		return a.column > b.column
+	})
+	changed := false
+	for _, r := range replacements {
+		if r.line < 1 || r.line > len(lines) {
+			// the position is out of the file, for example the file is changed after it is checked
+			continue
+		}
+		line := newLines[r.line-1]
+		start := columnToOffset(line, r.column)
+		if start == -1 || !strings.HasPrefix(line[start:], r.old) {
+			// the url in the file is different from the url, for example the url is resolved or unescaped
+			fmt.Fprintf(os.Stderr, "[WARN] skip fixing %s at %s:%d:%d because the text isn't the url\n", r.old, p, r.line, r.column)
+			continue
+		}
+		newLines[r.line-1] = line[:start] + r.new + line[start+len(r.old):]
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	if dryRun {
+		_, err := io.WriteString(w, unifiedDiff(p, lines, newLines))
+		return err
+	}
+	if err := lgc.fsys.Write(p, []byte(strings.Join(newLines, ""))); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "fixed %s\n", p)
+	return err
+}
+
+// columnToOffset converts the column in characters starting at 1 to the byte offset in the line.
+// If the column is out of the line, -1 is returned.
+func columnToOffset(line string, column int) int {
+	col := 1
+	for i := range line {
+		if col == column {
+			return i
+		}
+		col++
+	}
+	return -1
+}
+
+// unifiedDiff returns the unified diff of the file whose lines are replaced.
+// The number of lines of oldLines and newLines must be same.
+func unifiedDiff(p string, oldLines, newLines []string) string {
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "--- a/%s\n+++ b/%s\n", p, p)
+	for i := 0; i < len(oldLines); i++ {
+		if oldLines[i] == newLines[i] {
+			continue
+		}
+		// a hunk from start to end (exclusive)
+		start := i - diffContextLines
+		if start < 0 {
+			start = 0
+		}
+		end := i + 1
+		for j := i + 1; j < len(oldLines) && j <= end+diffContextLines*2; j++ {
+			if oldLines[j] != newLines[j] {
+				end = j + 1
+			}
+		}
+		end += diffContextLines
+		if end > len(oldLines) {
+			end = len(oldLines)
+		}
+		if end > start && oldLines[end-1] == "" {
+			// the empty string after the last newline isn't a line
+			end--
+		}
+		fmt.Fprintf(buf, "@@ -%s +%s @@\n", hunkRange(start, end), hunkRange(start, end))
+		for j := start; j < end; j++ {
+			if oldLines[j] == newLines[j] {
+				writeDiffLine(buf, " ", oldLines[j])
+				continue
+			}
+			// consecutive changed lines are output as removed lines followed by added lines
+			k := j
+			for k < end && oldLines[k] != newLines[k] {
+				k++
+			}
+			for _, line := range oldLines[j:k] {
+				writeDiffLine(buf, "-", line)
+			}
+			for _, line := range newLines[j:k] {
+				writeDiffLine(buf, "+", line)
+			}
+			j = k - 1
+		}
+		i = end - 1
+	}
+	return buf.String()
+}
+
+func hunkRange(start, end int) string {
+	if end-start == 1 {
+		return fmt.Sprint(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, end-start)
+}
+
+func writeDiffLine(buf *strings.Builder, prefix, line string) {
+	buf.WriteString(prefix)
+	buf.WriteString(line)
+	if !strings.HasSuffix(line, "\n") {
+		buf.WriteString("\n\\ No newline at end of file\n")
+	}
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+	"github.com/suzuki-shunsuke/durl/internal/test"
+)
+
+func Test_logicFix(t *testing.T) {
+	files := map[string]string{
+		"README.md": `# durl
+
+See https://old.example.com/foo and [docs](https://old.example.com/foo).
+
+1
+2
+3
+4
+5
+6
+7
+8
+https://ok.example.com https://old.example.com/bar`,
+		"docs/index.html": "<a href=\"https://old.example.com/foo\">foo</a>\n",
+	}
+	results := []domain.Result{
+		{
+			URL: "https://old.example.com/foo", SuggestedURL: "https://new.example.com/foo",
+			Locations: []domain.Location{
+				{Path: "README.md", Line: 3, Column: 5},
+				{Path: "README.md", Line: 3, Column: 44},
+				{Path: "docs/index.html", Line: 1, Column: 10},
+			},
+		},
+		{
+			URL: "https://old.example.com/bar", SuggestedURL: "https://new.example.com/bar",
+			ErrorKind: domain.ErrorKindRedirect, Error: "https://old.example.com/bar is permanently redirected",
+			Locations: []domain.Location{{Path: "README.md", Line: 13, Column: 24}},
+		},
+		{
+			// the position doesn't match the url
+			URL: "https://old.example.com/baz", SuggestedURL: "https://new.example.com/baz",
+			Locations: []domain.Location{{Path: "docs/index.html", Line: 1, Column: 1}},
+		},
+		{
+			// dead urls aren't fixed
+			URL: "https://ok.example.com", SuggestedURL: "https://new.example.com",
+			ErrorKind: domain.ErrorKindHTTPClientError,
+			Locations: []domain.Location{{Path: "README.md", Line: 13, Column: 1}},
+		},
+		{
+			// urls from stdin don't have positions
+			URL: "https://old.example.com/foo", SuggestedURL: "https://new.example.com/foo",
+			Locations: []domain.Location{{Path: "-"}},
+		},
+	}
+	newFsys := func(written map[string]string) domain.Fsys {
+		return test.NewFsys(t, nil).
+			SetFuncOpen(func(p string) (io.ReadCloser, error) {
+				return ioutil.NopCloser(strings.NewReader(files[p])), nil
+			}).
+			SetFuncWrite(func(p string, b []byte) error {
+				written[p] = string(b)
+				return nil
+			})
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		written := map[string]string{}
+		lgc := &logic{fsys: newFsys(written)}
+		buf := &bytes.Buffer{}
+		require.Nil(t, lgc.Fix(results, true, buf))
+		require.Empty(t, written)
+		require.Equal(t, `--- a/README.md
++++ b/README.md
+@@ -1,6 +1,6 @@
+ # durl
+ 
+-See https://old.example.com/foo and [docs](https://old.example.com/foo).
++See https://new.example.com/foo and [docs](https://new.example.com/foo).
+ 
+ 1
+ 2
+@@ -10,4 +10,4 @@
+ 6
+ 7
+ 8
+-https://ok.example.com https://old.example.com/bar
+\ No newline at end of file
++https://ok.example.com https://new.example.com/bar
+\ No newline at end of file
+--- a/docs/index.html
++++ b/docs/index.html
+@@ -1 +1 @@
+-<a href="https://old.example.com/foo">foo</a>
++<a href="https://new.example.com/foo">foo</a>
+`, buf.String())
+	})
+
+	t.Run("write", func(t *testing.T) {
+		written := map[string]string{}
+		lgc := &logic{fsys: newFsys(written)}
+		buf := &bytes.Buffer{}
+		require.Nil(t, lgc.Fix(results, false, buf))
+		require.Equal(t, "fixed README.md\nfixed docs/index.html\n", buf.String())
+		require.Equal(t, map[string]string{
+			"README.md":       strings.ReplaceAll(files["README.md"], "old.example.com", "new.example.com"),
+			"docs/index.html": "<a href=\"https://new.example.com/foo\">foo</a>\n",
+		}, written)
+	})
+}
+
+func Test_columnToOffset(t *testing.T) {
+	data := []struct {
+		title  string
+		line   string
+		column int
+		exp    int
+	}{
+		{"first", "https://example.com", 1, 0},
+		{"multibyte", "日本 https://example.com", 4, 7},
+		{"out of line", "foo", 4, -1},
+	}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			require.Equal(t, tt.exp, columnToOffset(tt.line, tt.column))
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// compileGlob converts a glob pattern to a regular expression.
+// "*" matches any sequence of characters except "/",
+// "?" matches any single character except "/",
+// "**" matches any sequence of characters including "/" and
+// "[...]" matches a character class.
+// A pattern which doesn't include "/" matches the base name of a path.
+func compileGlob(pattern string) (*regexp.Regexp, error) {
+	p := strings.TrimPrefix(pattern, "/")
+	if p == "" {
+		return nil, fmt.Errorf("glob pattern is empty")
+	}
+	buf := strings.Builder{}
+	if strings.Contains(p, "/") {
+		buf.WriteString("^")
+	} else {
+		buf.WriteString("(?:^|/)")
+	}
+	for i := 0; i < len(p); i++ {
+		c := p[i]
+		switch c {
+		case '*':
+			if i+1 < len(p) && p[i+1] == '*' {
+				i++
+				if i+1 < len(p) && p[i+1] == '/' {
+					// "**/" matches zero or more directories
+					i++
+					buf.WriteString("(?:.*/)?")
+					continue
+				}
+				buf.WriteString(".*")
+				continue
+			}
+			buf.WriteString("[^/]*")
+		case '?':
+			buf.WriteString("[^/]")
+		case '/':
+			if p[i+1:] == "**" {
+				// "/**" at the end matches the directory itself and everything under it
+				buf.WriteString("(?:/.*)?")
+				i = len(p)
+				continue
+			}
+			buf.WriteString("/")
+		case '[':
+			end := strings.IndexByte(p[i+1:], ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid glob pattern %s: [ isn't closed", pattern)
+			}
+			class := p[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			buf.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			buf.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	buf.WriteString("$")
+	reg, err := regexp.Compile(buf.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob pattern %s: %w", pattern, err)
+	}
+	return reg, nil
+}
+
+// compileGlobs compiles glob patterns.
+func compileGlobs(patterns []string) ([]*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	regs := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		reg, err := compileGlob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		regs[i] = reg
+	}
+	return regs, nil
+}
+
+// cleanPath converts a file path to a slash separated path without the leading "./" .
+func cleanPath(p string) string {
+	return filepath.ToSlash(filepath.Clean(p))
+}
+
+func matchPatterns(patterns []*regexp.Regexp, p string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// isExcludedPath returns true if the path or one of its parent directories matches the exclude patterns.
+func (lgc *logic) isExcludedPath(p string) bool {
+	if len(lgc.cfg.ExcludePatterns) == 0 {
+		return false
+	}
+	for p = cleanPath(p); p != "." && p != "/"; p = path.Dir(p) {
+		if matchPatterns(lgc.cfg.ExcludePatterns, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTargetFile returns true if the file should be checked according to include and exclude patterns.
+// The cache file is never checked.
+func (lgc *logic) isTargetFile(p string) bool {
+	if lgc.isCacheFile(p) || lgc.isExcludedPath(p) {
+		return false
+	}
+	if len(lgc.cfg.IncludePatterns) == 0 {
+		return true
+	}
+	return matchPatterns(lgc.cfg.IncludePatterns, cleanPath(p))
+}
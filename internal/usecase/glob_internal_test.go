@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_compileGlob(t *testing.T) {
+	data := []struct {
+		pattern string
+		path    string
+		exp     bool
+	}{
+		{"*.md", "README.md", true},
+		{"*.md", "docs/README.md", true},
+		{"*.md", "README.txt", false},
+		{"docs/*.md", "docs/README.md", true},
+		{"docs/*.md", "docs/foo/README.md", false},
+		{"docs/**/*.md", "docs/README.md", true},
+		{"docs/**/*.md", "docs/foo/bar/README.md", true},
+		{"docs/**", "docs", true},
+		{"docs/**", "docs/foo/bar.txt", true},
+		{"docs/**", "docs2/foo.txt", false},
+		{"node_modules", "foo/node_modules", true},
+		{"/vendor", "vendor", true},
+		{"file?.txt", "file1.txt", true},
+		{"file[0-9].txt", "file1.txt", true},
+		{"file[!0-9].txt", "file1.txt", false},
+	}
+	for _, d := range data {
+		reg, err := compileGlob(d.pattern)
+		require.Nil(t, err, d.pattern)
+		require.Equal(t, d.exp, reg.MatchString(d.path), d.pattern+" "+d.path)
+	}
+	for _, pattern := range []string{"", "file[0-9.txt"} {
+		_, err := compileGlob(pattern)
+		require.NotNil(t, err, pattern)
+	}
+}
@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// hostPauses manages periods in which requests to hosts are paused
+// because the hosts return 429 Too Many Requests or 503 Service Unavailable with the Retry-After header.
+// The zero value is ready to use.
+type hostPauses struct {
+	mutex sync.Mutex
+	// host -> the time until which requests to the host are paused
+	until map[string]time.Time
+}
+
+// pause pauses requests to the host for the duration.
+func (pauses *hostPauses) pause(host string, d time.Duration) {
+	pauses.mutex.Lock()
+	defer pauses.mutex.Unlock()
+	if pauses.until == nil {
+		pauses.until = map[string]time.Time{}
+	}
+	until := time.Now().Add(d)
+	if until.After(pauses.until[host]) {
+		pauses.until[host] = until
+	}
+}
+
+// remaining returns the remaining duration of the pause of the host.
+func (pauses *hostPauses) remaining(host string) time.Duration {
+	pauses.mutex.Lock()
+	defer pauses.mutex.Unlock()
+	return time.Until(pauses.until[host])
+}
+
+// globalSlotKey is the context key of the semaphore of max_request_count whose slot the request holds.
+type globalSlotKey struct{}
+
+// withGlobalSlot returns the context of the request which holds a slot of the semaphore.
+func withGlobalSlot(ctx context.Context, semaphore chan struct{}) context.Context {
+	return context.WithValue(ctx, globalSlotKey{}, semaphore)
+}
+
+// sleepWithoutGlobalSlot waits for the duration like sleep.
+// If the request holds a slot of max_request_count, the slot is released while sleeping
+// so that requests to the other hosts aren't blocked by the sleeping request, and it is acquired again after sleeping.
+func sleepWithoutGlobalSlot(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	semaphore, _ := ctx.Value(globalSlotKey{}).(chan struct{})
+	if semaphore == nil {
+		return sleep(ctx, d)
+	}
+	<-semaphore
+	ok := sleep(ctx, d)
+	// the slot is acquired even if the context is canceled because the caller releases it
+	semaphore <- struct{}{}
+	return ok
+}
+
+// hostScheduler limits the number of parallel requests and the request rate to a host.
+type hostScheduler struct {
+	// semaphore limits the number of parallel requests. If it is nil, the number isn't limited.
+	semaphore chan struct{}
+	// interval is the minimum interval between requests. If it is 0, the rate isn't limited.
+	interval time.Duration
+	mutex    sync.Mutex
+	// next is the time when the next request can be sent
+	next time.Time
+}
+
+// hostSchedulers has a scheduler per host.
+// The zero value is ready to use.
+type hostSchedulers struct {
+	mutex      sync.Mutex
+	schedulers map[string]*hostScheduler
+}
+
+// acquire waits until the number of parallel requests to the host becomes less than the limit.
+// If the context is canceled, false is returned.
+// A nil scheduler doesn't limit anything.
+func (scheduler *hostScheduler) acquire(ctx context.Context) bool {
+	if scheduler == nil || scheduler.semaphore == nil {
+		return true
+	}
+	select {
+	case scheduler.semaphore <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (scheduler *hostScheduler) release() {
+	if scheduler == nil || scheduler.semaphore == nil {
+		return
+	}
+	<-scheduler.semaphore
+}
+
+// wait waits until a request to the host can be sent according to the request rate.
+// The slot of max_request_count isn't held while waiting.
+// If the context is canceled, false is returned.
+func (scheduler *hostScheduler) wait(ctx context.Context) bool {
+	if scheduler == nil || scheduler.interval == 0 {
+		return true
+	}
+	scheduler.mutex.Lock()
+	now := time.Now()
+	t := scheduler.next
+	if t.Before(now) {
+		t = now
+	}
+	scheduler.next = t.Add(scheduler.interval)
+	scheduler.mutex.Unlock()
+	return sleepWithoutGlobalSlot(ctx, t.Sub(now))
+}
+
+// getHostScheduler returns the scheduler of the host.
+// If the host is empty, nil is returned.
+func (lgc *logic) getHostScheduler(host string) *hostScheduler {
+	if host == "" {
+		return nil
+	}
+	lgc.schedulers.mutex.Lock()
+	defer lgc.schedulers.mutex.Unlock()
+	if scheduler, ok := lgc.schedulers.schedulers[host]; ok {
+		return scheduler
+	}
+	if lgc.schedulers.schedulers == nil {
+		lgc.schedulers.schedulers = map[string]*hostScheduler{}
+	}
+	maxRequestCount, requestsPerSecond := lgc.getHostLimits(host)
+	scheduler := &hostScheduler{}
+	if maxRequestCount > 0 {
+		scheduler.semaphore = make(chan struct{}, maxRequestCount)
+	}
+	if requestsPerSecond > 0 {
+		scheduler.interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	lgc.schedulers.schedulers[host] = scheduler
+	return scheduler
+}
+
+// getHostLimits returns per_host_max_request_count and per_host_requests_per_second of the host.
+// The setting of the host in hosts overrides the default,
+// and the setting without a port such as "example.com" is applied to "example.com:8080" too.
+func (lgc *logic) getHostLimits(host string) (int, float64) {
+	maxRequestCount := lgc.cfg.PerHostMaxRequestCount
+	requestsPerSecond := lgc.cfg.PerHostRequestsPerSecond
+	hostCfg, ok := lgc.cfg.Hosts[host]
+	if !ok {
+		hostCfg, ok = lgc.cfg.Hosts[stripPort(host)]
+	}
+	if !ok {
+		return maxRequestCount, requestsPerSecond
+	}
+	if hostCfg.MaxRequestCount != 0 {
+		maxRequestCount = hostCfg.MaxRequestCount
+	}
+	if hostCfg.RequestsPerSecond != 0 {
+		requestsPerSecond = hostCfg.RequestsPerSecond
+	}
+	return maxRequestCount, requestsPerSecond
+}
+
+// stripPort returns the host without the port.
+func stripPort(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
+
+// getHost returns the lower case host of the url.
+func getHost(u string) string {
+	a, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(a.Host)
+}
+
+// getRetryAfter returns the duration of the Retry-After header of the 429 or 503 response.
+// The value of the header is either seconds or a HTTP date.
+// The duration is at least one second so that a server can't make the checker retry without a pause.
+func getRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0, false
+	}
+	v := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+	if sec, err := strconv.Atoi(v); err == nil {
+		if sec < 0 {
+			return 0, false
+		}
+		return maxDuration(time.Duration(sec)*time.Second, time.Second), true
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	return maxDuration(time.Until(t), time.Second), true
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// waitHost waits while requests to the host are paused.
+// If the remaining pause exceeds the budget, it doesn't wait.
+// The slot of max_request_count isn't held while waiting.
+// The waited duration is returned. If the context is canceled, false is returned.
+func (lgc *logic) waitHost(ctx context.Context, host string, budget time.Duration) (time.Duration, bool) {
+	d := lgc.pauses.remaining(host)
+	if d <= 0 || d > budget {
+		return 0, true
+	}
+	return d, sleepWithoutGlobalSlot(ctx, d)
+}
+
+// initHosts validates the settings of requests per host.
+// Keys of hosts are converted to lower case.
+func initHosts(cfg domain.Cfg) (domain.Cfg, error) {
+	if cfg.PerHostMaxRequestCount < 0 || cfg.PerHostRequestsPerSecond < 0 {
+		return cfg, fmt.Errorf(`per_host_max_request_count and per_host_requests_per_second must not be negative`)
+	}
+	hosts := make(map[string]domain.HostCfg, len(cfg.Hosts))
+	for host, hostCfg := range cfg.Hosts {
+		if hostCfg.MaxRequestCount < 0 || hostCfg.RequestsPerSecond < 0 {
+			return cfg, fmt.Errorf(`max_request_count and requests_per_second of the host %s must not be negative`, host)
+		}
+		// hosts are compared in lower case
+		hosts[strings.ToLower(host)] = hostCfg
+	}
+	cfg.Hosts = hosts
+	return cfg, nil
+}
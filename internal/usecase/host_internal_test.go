@@ -0,0 +1,258 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/gomic/gomic"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+	"github.com/suzuki-shunsuke/durl/internal/test"
+)
+
+func Test_getRetryAfter(t *testing.T) {
+	data := []struct {
+		title      string
+		statusCode int
+		header     string
+		exp        time.Duration
+		ok         bool
+	}{
+		{"seconds", 429, "120", 2 * time.Minute, true},
+		{"zero", 503, "0", time.Second, true},
+		{"date in the past", 429, "Wed, 21 Oct 2015 07:28:00 GMT", time.Second, true},
+		{"invalid", 429, "foo", 0, false},
+		{"no header", 429, "", 0, false},
+		{"not 429 nor 503", 500, "120", 0, false},
+	}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			d, ok := getRetryAfter(&http.Response{
+				StatusCode: tt.statusCode,
+				Header:     http.Header{"Retry-After": []string{tt.header}},
+			})
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.exp, d)
+		})
+	}
+}
+
+func Test_logicCheckURLWithMethodRetryAfter(t *testing.T) {
+	data := []struct {
+		title    string
+		maxWait  time.Duration
+		attempts int
+		kind     string
+	}{
+		{"retried after the pause", 5 * time.Second, 2, ""},
+		{"exceed the max wait", 500 * time.Millisecond, 1, domain.ErrorKindHTTPClientError},
+		{"ignore Retry-After", -1, 1, domain.ErrorKindHTTPClientError},
+	}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			i := 0
+			lgc := &logic{
+				cfg: domain.Cfg{RetryAfterMaxWait: tt.maxWait},
+				client: test.NewHTTPClient(t, gomic.DoNothing).
+					SetFuncDo(func(req *http.Request) (*http.Response, error) {
+						i++
+						resp := &http.Response{
+							Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+							StatusCode: 200,
+						}
+						if i == 1 {
+							resp.StatusCode = 429
+							resp.Header = http.Header{"Retry-After": []string{"1"}}
+						}
+						return resp, nil
+					}),
+			}
+			start := time.Now()
+			result := lgc.CheckURLWithMethod(context.Background(), "http://example.com/foo", "get")
+			require.Equal(t, tt.kind, result.ErrorKind)
+			require.Equal(t, tt.attempts, result.Attempts)
+			if tt.attempts == 2 {
+				require.True(t, time.Since(start) >= 900*time.Millisecond)
+				// the pause of the host has ended
+				require.True(t, lgc.pauses.remaining("example.com") <= 0)
+			}
+		})
+	}
+}
+
+func Test_logicGetHostLimits(t *testing.T) {
+	lgc := &logic{cfg: domain.Cfg{
+		PerHostMaxRequestCount: 2, PerHostRequestsPerSecond: 5,
+		Hosts: map[string]domain.HostCfg{
+			"github.com":     {MaxRequestCount: 1},
+			"example.com:80": {RequestsPerSecond: 0.5},
+		},
+	}}
+	data := []struct {
+		host              string
+		maxRequestCount   int
+		requestsPerSecond float64
+	}{
+		{"gitlab.com", 2, 5},
+		{"github.com", 1, 5},
+		{"github.com:443", 1, 5},
+		{"example.com:80", 2, 0.5},
+		{"example.com", 2, 5},
+	}
+	for _, tt := range data {
+		maxRequestCount, requestsPerSecond := lgc.getHostLimits(tt.host)
+		require.Equal(t, tt.maxRequestCount, maxRequestCount, tt.host)
+		require.Equal(t, tt.requestsPerSecond, requestsPerSecond, tt.host)
+	}
+	require.Nil(t, lgc.getHostScheduler(""))
+	require.Same(t, lgc.getHostScheduler("github.com"), lgc.getHostScheduler("github.com"))
+}
+
+func Test_hostSchedulerWait(t *testing.T) {
+	scheduler := &hostScheduler{interval: 50 * time.Millisecond}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.True(t, scheduler.wait(context.Background()))
+	}
+	require.True(t, time.Since(start) >= 100*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.False(t, (&hostScheduler{semaphore: make(chan struct{})}).acquire(ctx))
+	var nilScheduler *hostScheduler
+	require.True(t, nilScheduler.acquire(ctx))
+	require.True(t, nilScheduler.wait(ctx))
+}
+
+func Test_logicCheckURLsPerHost(t *testing.T) {
+	// the number of parallel requests per host doesn't exceed per_host_max_request_count
+	mutex := sync.Mutex{}
+	current := map[string]int{}
+	maxCount := map[string]int{}
+	mock := test.NewLogic(t, gomic.DoNothing).
+		SetFuncCheckURL(func(ctx context.Context, u string) domain.Result {
+			host := getHost(u)
+			mutex.Lock()
+			current[host]++
+			if current[host] > maxCount[host] {
+				maxCount[host] = current[host]
+			}
+			mutex.Unlock()
+			time.Sleep(10 * time.Millisecond)
+			mutex.Lock()
+			current[host]--
+			mutex.Unlock()
+			return domain.Result{}
+		})
+	urls := map[string][]domain.Location{}
+	for i := 0; i < 10; i++ {
+		urls[fmt.Sprintf("https://github.com/%d", i)] = []domain.Location{{Path: "foo.txt"}}
+		urls[fmt.Sprintf("https://gitlab.com/%d", i)] = []domain.Location{{Path: "foo.txt"}}
+	}
+	lgc := &logic{
+		logic: mock,
+		cfg: domain.Cfg{
+			MaxRequestCount: 10, PerHostMaxRequestCount: 3,
+			Hosts: map[string]domain.HostCfg{"github.com": {MaxRequestCount: 1}},
+		},
+		reporter: test.NewReporter(t, gomic.DoNothing),
+	}
+	results, err := lgc.CheckURLs(urls)
+	require.Nil(t, err)
+	require.Len(t, results, 20)
+	require.Equal(t, 1, maxCount["github.com"])
+	require.LessOrEqual(t, maxCount["gitlab.com"], 3)
+}
+
+func Test_logicCheckURLsPerHostRate(t *testing.T) {
+	// a request waiting for the request rate of the host doesn't hold a slot of max_request_count,
+	// so the request to the other host isn't blocked
+	mutex := sync.Mutex{}
+	done := map[string]time.Duration{}
+	start := time.Now()
+	client := test.NewHTTPClient(t, nil).SetFuncDo(func(req *http.Request) (*http.Response, error) {
+		mutex.Lock()
+		done[req.URL.String()] = time.Since(start)
+		mutex.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(&bytes.Buffer{}),
+			Request:    req,
+		}, nil
+	})
+	urls := map[string][]domain.Location{
+		"https://gitlab.com/foo": {{Path: "foo.txt"}},
+	}
+	for i := 0; i < 8; i++ {
+		urls[fmt.Sprintf("https://github.com/%d", i)] = []domain.Location{{Path: "foo.txt"}}
+	}
+	lgc := &logic{
+		cfg: domain.Cfg{
+			HTTPMethod: "get", MaxRequestCount: 2, MaxFailedRequestCount: -1,
+			Hosts: map[string]domain.HostCfg{"github.com": {RequestsPerSecond: 10}},
+		},
+		client:   client,
+		reporter: test.NewReporter(t, gomic.DoNothing),
+	}
+	lgc.logic = lgc
+	results, err := lgc.CheckURLs(urls)
+	require.Nil(t, err)
+	require.Len(t, results, 9)
+	// github.com is requested at 10 requests per second, so the last request is sent after 700ms
+	last := time.Duration(0)
+	for u, d := range done {
+		if u != "https://gitlab.com/foo" && d > last {
+			last = d
+		}
+	}
+	require.GreaterOrEqual(t, int64(last), int64(700*time.Millisecond))
+	require.Less(t, int64(done["https://gitlab.com/foo"]), int64(300*time.Millisecond))
+}
+
+func Test_logicCheckURLsPausedHost(t *testing.T) {
+	// requests waiting for the pause of the host by Retry-After don't hold slots of max_request_count
+	mutex := sync.Mutex{}
+	done := map[string]time.Duration{}
+	start := time.Now()
+	client := test.NewHTTPClient(t, nil).SetFuncDo(func(req *http.Request) (*http.Response, error) {
+		mutex.Lock()
+		done[req.URL.String()] = time.Since(start)
+		mutex.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(&bytes.Buffer{}),
+			Request:    req,
+		}, nil
+	})
+	urls := map[string][]domain.Location{
+		"https://gitlab.com/foo": {{Path: "foo.txt"}},
+	}
+	for i := 0; i < 4; i++ {
+		urls[fmt.Sprintf("https://github.com/%d", i)] = []domain.Location{{Path: "foo.txt"}}
+	}
+	lgc := &logic{
+		cfg: domain.Cfg{
+			HTTPMethod: "get", MaxRequestCount: 2, MaxFailedRequestCount: -1, RetryAfterMaxWait: time.Minute,
+		},
+		client:   client,
+		reporter: test.NewReporter(t, gomic.DoNothing),
+	}
+	lgc.logic = lgc
+	lgc.pauses.pause("github.com", 500*time.Millisecond)
+	results, err := lgc.CheckURLs(urls)
+	require.Nil(t, err)
+	require.Len(t, results, 5)
+	for i := 0; i < 4; i++ {
+		require.GreaterOrEqual(t, int64(done[fmt.Sprintf("https://github.com/%d", i)]), int64(400*time.Millisecond))
+	}
+	require.Less(t, int64(done["https://gitlab.com/foo"]), int64(300*time.Millisecond))
+}
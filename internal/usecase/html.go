@@ -0,0 +1,317 @@
+package usecase
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// htmlLinkAttrs are attributes which have a url per element.
+var htmlLinkAttrs = map[string][]string{ //nolint:gochecknoglobals
+	"a":          {"href"},
+	"area":       {"href"},
+	"audio":      {"src"},
+	"blockquote": {"cite"},
+	"del":        {"cite"},
+	"embed":      {"src"},
+	"frame":      {"src"},
+	"iframe":     {"src"},
+	"img":        {"src", "srcset"},
+	"input":      {"src"},
+	"ins":        {"cite"},
+	"link":       {"href"},
+	"object":     {"data"},
+	"q":          {"cite"},
+	"script":     {"src"},
+	"source":     {"src", "srcset"},
+	"track":      {"src"},
+	"video":      {"src", "poster"},
+}
+
+// htmlRawAttr is an attribute in the raw start tag.
+type htmlRawAttr struct {
+	// value is the raw value which isn't unescaped.
+	value string
+	// offset is the byte offset of the value in the tag.
+	offset int
+}
+
+// htmlExtractor extracts urls from a HTML document.
+type htmlExtractor struct {
+	path  string
+	links []domain.Link
+	// line and column are the position of the start of the current token, which start at 1.
+	line   int
+	column int
+	// tag is the raw start tag which is being processed.
+	tag string
+	// base is the url of the base element.
+	base *url.URL
+}
+
+// extractURLsFromHTML extracts urls of link-bearing attributes from a HTML document.
+// Comments, scripts and texts are ignored.
+// Relative urls are resolved against the url of the base element if it exists.
+// The document is read as a stream, and the positions of urls are tracked while tokenizing.
+func extractURLsFromHTML(r io.Reader, p string) ([]domain.Link, error) {
+	ext := &htmlExtractor{
+		path:   p,
+		links:  []domain.Link{},
+		line:   1,
+		column: 1,
+	}
+	tokenizer := html.NewTokenizer(r)
+	for {
+		tt := tokenizer.Next()
+		if tt == html.ErrorToken {
+			if err := tokenizer.Err(); err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+		// the raw token is copied because it is changed by the next call of Next
+		raw := string(tokenizer.Raw())
+		if tt == html.StartTagToken || tt == html.SelfClosingTagToken {
+			name, _ := tokenizer.TagName()
+			ext.tag = raw
+			ext.extractFromTag(string(name))
+		}
+		ext.line, ext.column = advancePosition(ext.line, ext.column, raw)
+	}
+	if ext.base != nil {
+		for i, link := range ext.links {
+			if u, err := url.Parse(link.URL); err == nil {
+				ext.links[i].URL = ext.base.ResolveReference(u).String()
+			}
+		}
+	}
+	return ext.links, nil
+}
+
+// advancePosition returns the position after the text which starts at the line and the column.
+// The column is counted in characters.
+func advancePosition(line, column int, text string) (int, int) {
+	for _, c := range text {
+		if c == '\n' {
+			line++
+			column = 1
+			continue
+		}
+		column++
+	}
+	return line, column
+}
+
+// extractFromTag extracts urls from the current start tag.
+func (ext *htmlExtractor) extractFromTag(name string) {
+	attrs := parseHTMLRawAttrs(ext.tag)
+	switch name {
+	case "base":
+		if attr, ok := attrs["href"]; ok && ext.base == nil {
+			if u, err := url.Parse(strings.TrimSpace(html.UnescapeString(attr.value))); err == nil && u.IsAbs() {
+				ext.base = u
+			}
+		}
+		return
+	case "link":
+		// the href of preconnect and dns-prefetch is an origin, which isn't a link to a resource
+		if attr, ok := attrs["rel"]; ok {
+			for _, rel := range strings.Fields(strings.ToLower(html.UnescapeString(attr.value))) {
+				if rel == "preconnect" || rel == "dns-prefetch" {
+					return
+				}
+			}
+		}
+	case "meta":
+		if attr, ok := attrs["http-equiv"]; !ok || !strings.EqualFold(strings.TrimSpace(html.UnescapeString(attr.value)), "refresh") {
+			return
+		}
+		if attr, ok := attrs["content"]; ok {
+			if start, end, ok := getMetaRefreshURLRange(attr.value); ok {
+				ext.add(attr.value[start:end], attr.offset+start, "meta[http-equiv=refresh]")
+			}
+		}
+		return
+	}
+	for _, key := range htmlLinkAttrs[name] {
+		attr, ok := attrs[key]
+		if !ok {
+			continue
+		}
+		context := name + "[" + key + "]"
+		if key == "srcset" {
+			for _, c := range parseSrcset(attr.value) {
+				ext.add(attr.value[c[0]:c[1]], attr.offset+c[0], context)
+			}
+			continue
+		}
+		ext.add(attr.value, attr.offset, context)
+	}
+}
+
+// add adds a url. raw is the raw url which isn't unescaped and offset is the byte offset of raw in the tag.
+func (ext *htmlExtractor) add(raw string, offset int, context string) {
+	// leading and trailing spaces of a url are ignored
+	trimmed := strings.TrimLeft(raw, " \t\r\n\f")
+	offset += len(raw) - len(trimmed)
+	u := strings.TrimSpace(html.UnescapeString(trimmed))
+	if u == "" {
+		return
+	}
+	line, column := advancePosition(ext.line, ext.column, ext.tag[:offset])
+	ext.links = append(ext.links, domain.Link{
+		URL: u,
+		Location: domain.Location{
+			Path:    ext.path,
+			Line:    line,
+			Column:  column,
+			Context: context,
+		},
+	})
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// parseHTMLRawAttrs parses attributes of a raw start tag.
+// The key of the returned map is the lower case attribute name.
+// If the attribute is duplicated, the first one is used.
+func parseHTMLRawAttrs(raw string) map[string]htmlRawAttr {
+	attrs := map[string]htmlRawAttr{}
+	// skip "<" and the tag name
+	i := 1
+	for i < len(raw) && !isHTMLSpace(raw[i]) && raw[i] != '/' && raw[i] != '>' {
+		i++
+	}
+	for i < len(raw) {
+		for i < len(raw) && (isHTMLSpace(raw[i]) || raw[i] == '/') {
+			i++
+		}
+		if i >= len(raw) || raw[i] == '>' {
+			break
+		}
+		start := i
+		i++
+		for i < len(raw) && !isHTMLSpace(raw[i]) && raw[i] != '/' && raw[i] != '>' && raw[i] != '=' {
+			i++
+		}
+		key := strings.ToLower(raw[start:i])
+		for i < len(raw) && isHTMLSpace(raw[i]) {
+			i++
+		}
+		if i >= len(raw) || raw[i] != '=' {
+			// an attribute without value
+			if _, ok := attrs[key]; !ok {
+				attrs[key] = htmlRawAttr{offset: i}
+			}
+			continue
+		}
+		i++
+		for i < len(raw) && isHTMLSpace(raw[i]) {
+			i++
+		}
+		var attr htmlRawAttr
+		if i < len(raw) && (raw[i] == '"' || raw[i] == '\'') {
+			end := strings.IndexByte(raw[i+1:], raw[i])
+			if end == -1 {
+				end = len(raw) - i - 1
+			}
+			attr = htmlRawAttr{value: raw[i+1 : i+1+end], offset: i + 1}
+			i += end + 2
+		} else {
+			start := i
+			for i < len(raw) && !isHTMLSpace(raw[i]) && raw[i] != '>' {
+				i++
+			}
+			attr = htmlRawAttr{value: raw[start:i], offset: start}
+		}
+		if _, ok := attrs[key]; !ok {
+			attrs[key] = attr
+		}
+	}
+	return attrs
+}
+
+// parseSrcset returns ranges of urls in the srcset attribute such as "foo.png 1x, bar.png 2x".
+func parseSrcset(s string) [][2]int {
+	ranges := [][2]int{}
+	i := 0
+	for {
+		for i < len(s) && (isHTMLSpace(s[i]) || s[i] == ',') {
+			i++
+		}
+		if i >= len(s) {
+			return ranges
+		}
+		start := i
+		for i < len(s) && !isHTMLSpace(s[i]) {
+			i++
+		}
+		end := i
+		if s[end-1] == ',' {
+			// a url followed by commas has no descriptor
+			for end > start && s[end-1] == ',' {
+				end--
+			}
+			ranges = append(ranges, [2]int{start, end})
+			continue
+		}
+		ranges = append(ranges, [2]int{start, end})
+		// skip descriptors
+		depth := 0
+		for ; i < len(s); i++ {
+			if s[i] == '(' {
+				depth++
+			} else if s[i] == ')' && depth > 0 {
+				depth--
+			} else if s[i] == ',' && depth == 0 {
+				break
+			}
+		}
+	}
+}
+
+// getMetaRefreshURLRange returns the range of the url in the content attribute of the meta refresh
+// such as "5; url=https://example.com".
+// If the content doesn't have a url, false is returned.
+func getMetaRefreshURLRange(content string) (int, int, bool) {
+	i := 0
+	for i < len(content) && (content[i] == ' ' || ('0' <= content[i] && content[i] <= '9') || content[i] == '.') {
+		i++
+	}
+	if i >= len(content) || (content[i] != ';' && content[i] != ',') {
+		return 0, 0, false
+	}
+	i++
+	for i < len(content) && content[i] == ' ' {
+		i++
+	}
+	if len(content)-i >= 3 && strings.EqualFold(content[i:i+3], "url") {
+		j := i + 3
+		for j < len(content) && content[j] == ' ' {
+			j++
+		}
+		if j < len(content) && content[j] == '=' {
+			i = j + 1
+			for i < len(content) && content[i] == ' ' {
+				i++
+			}
+		}
+	}
+	end := len(content)
+	if i < len(content) && (content[i] == '"' || content[i] == '\'') {
+		if j := strings.IndexByte(content[i+1:], content[i]); j != -1 {
+			end = i + 1 + j
+		}
+		i++
+	}
+	if i >= end {
+		return 0, 0, false
+	}
+	return i, end, true
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+func Test_extractURLsFromHTML(t *testing.T) {
+	type link struct {
+		url     string
+		line    int
+		col     int
+		context string
+	}
+	data := []struct {
+		title string
+		in    string
+		exp   []link
+	}{{
+		"attributes", strings.Join([]string{
+			`<html><head>`,
+			`<link rel="stylesheet" href="/css/main.css">`,
+			`<link rel="preconnect" href="https://fonts.gstatic.com">`,
+			`<meta http-equiv="refresh" content="5; url='https://example.com/new'">`,
+			`<script src=https://example.com/a.js>var u = "https://example.com/script";</script>`,
+			`</head><body>`,
+			`<!-- <a href="https://example.com/comment">comment</a> -->`,
+			`<p>https://example.com/text <A class="x" HREF = "https://example.com/a?b=1&amp;c=2">あ</A></p>`,
+			`<img src="img/a.png" srcset="img/a.png 1x, img/b,c.png 2x,img/d.png">`,
+			`</body></html>`,
+		}, "\n"), []link{
+			{"/css/main.css", 2, 30, "link[href]"},
+			{"https://example.com/new", 4, 45, "meta[http-equiv=refresh]"},
+			{"https://example.com/a.js", 5, 13, "script[src]"},
+			{"https://example.com/a?b=1&c=2", 8, 50, "a[href]"},
+			{"img/a.png", 9, 11, "img[src]"},
+			{"img/a.png", 9, 30, "img[srcset]"},
+			{"img/b,c.png", 9, 44, "img[srcset]"},
+			{"img/d.png", 9, 59, "img[srcset]"},
+		},
+	}, {
+		"base", strings.Join([]string{
+			`<a href="foo/bar.html#baz">`,
+			`<base href="https://example.com/docs/">`,
+			`<a href="https://example.org/">`,
+			`<img src="/img/a.png">`,
+		}, "\n"), []link{
+			{"https://example.com/docs/foo/bar.html#baz", 1, 10, "a[href]"},
+			{"https://example.org/", 3, 10, "a[href]"},
+			{"https://example.com/img/a.png", 4, 11, "img[src]"},
+		},
+	}, {
+		"multiline tag after a long text", strings.Join([]string{
+			strings.Repeat("あ", 100000),
+			`<p>text</p><a class="x"`,
+			`  href="https://example.com/a"`,
+			`>a</a>`,
+		}, "\n"), []link{
+			{"https://example.com/a", 3, 9, "a[href]"},
+		},
+	}}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			links, err := extractURLsFromHTML(strings.NewReader(tt.in), "index.html")
+			require.Nil(t, err)
+			exp := make([]domain.Link, len(tt.exp))
+			for i, l := range tt.exp {
+				exp[i] = domain.Link{
+					URL: l.url,
+					Location: domain.Location{
+						Path: "index.html", Line: l.line, Column: l.col, Context: l.context,
+					},
+				}
+			}
+			require.Equal(t, exp, links)
+		})
+	}
+}
+
+func Test_parseSrcset(t *testing.T) {
+	data := []struct {
+		in  string
+		exp []string
+	}{
+		{"", nil},
+		{"a.png", []string{"a.png"}},
+		{" a.png 1x , b.png 2x", []string{"a.png", "b.png"}},
+		{"a.png,, b.png 100w", []string{"a.png", "b.png"}},
+		{"a.png (foo, bar), b.png", []string{"a.png", "b.png"}},
+	}
+	for _, tt := range data {
+		var urls []string
+		for _, r := range parseSrcset(tt.in) {
+			urls = append(urls, tt.in[r[0]:r[1]])
+		}
+		require.Equal(t, tt.exp, urls, tt.in)
+	}
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// compileWildcard converts a pattern of ignore_urls and ignore_hosts which includes "*" to a regular expression.
+// "*" matches any sequence of characters, and the pattern must match the whole string.
+func compileWildcard(pattern string) *regexp.Regexp {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// compileHostPattern converts an entry of ignore_hosts to a regular expression which matches the host of the url in lower case.
+// An entry without a port such as "localhost" matches the host with any port,
+// and an entry with a port such as "localhost:8080" matches only the port.
+// "*" matches any sequence of characters.
+func compileHostPattern(entry string) (*regexp.Regexp, error) {
+	host, port := strings.ToLower(entry), ""
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		host, port = h, p
+	} else if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1]
+	}
+	if host == "" {
+		return nil, fmt.Errorf("ignore_hosts must not include an empty host")
+	}
+	if strings.Contains(host, ":") {
+		// IPv6 address
+		host = "[" + host + "]"
+	}
+	parts := strings.Split(host, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	portPattern := "(?::[0-9]+)?"
+	if port != "" {
+		portPattern = ":" + regexp.QuoteMeta(port)
+	}
+	return regexp.Compile("^" + strings.Join(parts, ".*") + portPattern + "$")
+}
+
+// initIgnores compiles ignore_urls, ignore_url_patterns and ignore_hosts.
+// Entries of ignore_urls which include "*" are compiled as wildcard patterns,
+// and the other entries are compared as they are.
+// All entries of ignore_hosts are compiled, and the default hosts are added unless default_ignore_hosts is false.
+func initIgnores(cfg domain.Cfg) (domain.Cfg, error) {
+	cfg.IgnoreURLRegexps = nil
+	cfg.IgnoreHostRegexps = nil
+	for _, u := range cfg.IgnoreURLs {
+		if strings.Contains(u, "*") {
+			cfg.IgnoreURLRegexps = append(cfg.IgnoreURLRegexps, compileWildcard(u))
+		}
+	}
+	for _, pattern := range cfg.IgnoreURLPatterns {
+		reg, err := regexp.Compile(pattern)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid ignore_url_patterns: %w", err)
+		}
+		cfg.IgnoreURLRegexps = append(cfg.IgnoreURLRegexps, reg)
+	}
+	hosts := cfg.IgnoreHosts
+	if cfg.DefaultIgnoreHosts == nil || *cfg.DefaultIgnoreHosts {
+		hosts = append(append([]string{}, domain.DefaultIgnoreHosts...), hosts...)
+	}
+	for _, host := range hosts {
+		reg, err := compileHostPattern(host)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid ignore_hosts %s: %w", host, err)
+		}
+		cfg.IgnoreHostRegexps = append(cfg.IgnoreHostRegexps, reg)
+	}
+	return cfg, nil
+}
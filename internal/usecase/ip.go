@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// privateCIDRs are ranges of private (RFC 1918 and RFC 4193), loopback and link-local addresses.
+var privateCIDRs = []string{ //nolint:gochecknoglobals
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+}
+
+// parseCIDR parses a CIDR such as "10.0.0.0/8". A single IP address such as "10.0.0.1" is also accepted.
+func parseCIDR(cidr string) (*net.IPNet, error) {
+	if !strings.Contains(cidr, "/") {
+		ip := net.ParseIP(cidr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid CIDR: %s", cidr)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, ipNet, err := net.ParseCIDR(cidr)
+	return ipNet, err
+}
+
+// initIPNets parses ignore_cidrs and adds private addresses if ignore_private_ips is true.
+func initIPNets(cfg domain.Cfg) (domain.Cfg, error) {
+	cidrs := cfg.IgnoreCIDRs
+	if cfg.IgnorePrivateIPs {
+		cidrs = append(append([]string{}, cidrs...), privateCIDRs...)
+	}
+	ipNets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		ipNet, err := parseCIDR(cidr)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid ignore_cidrs: %w", err)
+		}
+		ipNets = append(ipNets, ipNet)
+	}
+	cfg.IgnoreIPNets = ipNets
+	return cfg, nil
+}
+
+// isIgnoredIP returns true if the IP address is in ignore_cidrs.
+func (lgc *logic) isIgnoredIP(ip net.IP) bool {
+	for _, ipNet := range lgc.cfg.IgnoreIPNets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterResolvedURLs removes urls whose host resolves to an address in ignore_cidrs.
+// Hosts are resolved in parallel, and a host which can't be resolved isn't ignored
+// so that the url is reported as dead by the check.
+func (lgc *logic) filterResolvedURLs(urls map[string][]domain.Location) {
+	if len(lgc.cfg.IgnoreIPNets) == 0 {
+		return
+	}
+	// host -> urls
+	hosts := map[string][]string{}
+	for u := range urls {
+		uu, err := url.Parse(u)
+		if err != nil {
+			continue
+		}
+		host := strings.ToLower(uu.Hostname())
+		if host == "" || net.ParseIP(host) != nil {
+			// IP addresses are checked by IsIgnoredURL
+			continue
+		}
+		hosts[host] = append(hosts[host], u)
+	}
+	maxRequestCount := lgc.cfg.MaxRequestCount
+	if maxRequestCount <= 0 {
+		maxRequestCount = domain.DefaultMaxRequestCount
+	}
+	semaphore := make(chan struct{}, maxRequestCount)
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	for host, us := range hosts {
+		wg.Add(1)
+		go func(host string, us []string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			ignored := lgc.isIgnoredHost(host)
+			<-semaphore
+			if !ignored {
+				return
+			}
+			mutex.Lock()
+			for _, u := range us {
+				delete(urls, u)
+			}
+			mutex.Unlock()
+		}(host, us)
+	}
+	wg.Wait()
+}
+
+// isIgnoredHost returns true if any address of the host is in ignore_cidrs.
+func (lgc *logic) isIgnoredHost(host string) bool {
+	timeout := time.Duration(lgc.cfg.HTTPRequestTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = domain.DefaultTimeout * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	addrs, err := lgc.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if lgc.isIgnoredIP(addr.IP) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+	"github.com/suzuki-shunsuke/durl/internal/test"
+)
+
+func Test_parseCIDR(t *testing.T) {
+	data := []struct {
+		cidr  string
+		exp   string
+		isErr bool
+	}{
+		{"10.0.0.0/8", "10.0.0.0/8", false},
+		{"10.0.0.1", "10.0.0.1/32", false},
+		{"fd00::/8", "fd00::/8", false},
+		{"::1", "::1/128", false},
+		{"10.0.0.0/33", "", true},
+		{"internal.corp", "", true},
+	}
+	for _, tt := range data {
+		ipNet, err := parseCIDR(tt.cidr)
+		if tt.isErr {
+			require.NotNil(t, err, tt.cidr)
+			continue
+		}
+		require.Nil(t, err, tt.cidr)
+		require.Equal(t, tt.exp, ipNet.String())
+	}
+}
+
+func Test_logicIsIgnoredURLIP(t *testing.T) {
+	data := []struct {
+		title string
+		url   string
+		cfg   domain.Cfg
+		exp   bool
+	}{
+		{"private", "http://10.0.0.5/wiki", domain.Cfg{IgnorePrivateIPs: true}, true},
+		{"private with port", "http://192.168.1.1:8080", domain.Cfg{IgnorePrivateIPs: true}, true},
+		{"IPv6 loopback", "http://[::1]:8080/", domain.Cfg{IgnorePrivateIPs: true}, true},
+		{"link-local", "http://169.254.169.254/latest/meta-data", domain.Cfg{IgnorePrivateIPs: true}, true},
+		{"public", "http://172.32.0.1", domain.Cfg{IgnorePrivateIPs: true}, false},
+		{"disabled", "http://10.0.0.5/wiki", domain.Cfg{}, false},
+		{"ignore_cidrs", "http://100.64.0.1", domain.Cfg{IgnoreCIDRs: []string{"100.64.0.0/10"}}, true},
+	}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			cfg, err := initIPNets(tt.cfg)
+			require.Nil(t, err)
+			lgc := NewLogic(cfg, nil, nil, nil, nil)
+			require.Equal(t, tt.exp, lgc.IsIgnoredURL(tt.url))
+		})
+	}
+}
+
+func Test_logicFilterResolvedURLs(t *testing.T) {
+	cfg, err := initIPNets(domain.Cfg{IgnorePrivateIPs: true, IgnoreCIDRs: []string{"100.64.0.0/10"}})
+	require.Nil(t, err)
+	answers := map[string][]string{
+		"wiki.internal.corp": {"10.0.0.5"},
+		"vpn.corp":           {"100.64.0.1"},
+		"github.com":         {"140.82.112.3"},
+		"dual.corp":          {"140.82.112.4", "fd00::1"},
+	}
+	lgc := &logic{
+		cfg: cfg,
+		resolver: test.NewResolver(t, nil).SetFuncLookupIPAddr(func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			ips, ok := answers[host]
+			if !ok {
+				return nil, fmt.Errorf("no such host: %s", host)
+			}
+			addrs := make([]net.IPAddr, len(ips))
+			for i, ip := range ips {
+				addrs[i] = net.IPAddr{IP: net.ParseIP(ip)}
+			}
+			return addrs, nil
+		}),
+	}
+	loc := domain.Location{Path: "runbook.md", Line: 1, Column: 1}
+	urls := map[string][]domain.Location{
+		"https://wiki.internal.corp/runbook": {loc},
+		"https://Wiki.Internal.Corp:8443/":   {loc},
+		"https://vpn.corp":                   {loc},
+		"https://github.com/foo":             {loc},
+		"https://dual.corp":                  {loc},
+		"https://unknown.corp":               {loc},
+	}
+	lgc.filterResolvedURLs(urls)
+	require.Equal(t, map[string][]domain.Location{
+		"https://github.com/foo": {loc},
+		"https://unknown.corp":   {loc},
+	}, urls)
+}
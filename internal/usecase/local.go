@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// isHTTPURL returns true if the url's scheme is "http" or "https".
+// The other urls in the map passed to CheckURLs are paths of local files.
+func isHTTPURL(u string) bool {
+	for _, prefix := range []string{"http://", "https://"} {
+		if len(u) >= len(prefix) && strings.EqualFold(u[:len(prefix)], prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractLocalLinks removes relative links such as "../guide/setup.md" from urls
+// and returns a map whose key is the path of the linked local file and value is locations of links.
+// A relative link is resolved against the directory of the file which has the link,
+// and a root-relative link such as "/docs/setup.md" is resolved against docs_root.
+// If docs_root is empty, root-relative links are ignored.
+// If check_anchors is true, the fragment of the link is kept as the suffix "#fragment" of the key,
+// and a link to an anchor in the same file such as "#usage" is resolved to the file.
+func (lgc *logic) extractLocalLinks(urls map[string][]domain.Location) map[string][]domain.Location {
+	links := map[string][]domain.Location{}
+	for link, locs := range urls {
+		u, err := url.Parse(link)
+		if err != nil || u.Scheme != "" || u.Host != "" || strings.HasPrefix(link, "//") {
+			continue
+		}
+		delete(urls, link)
+		if lgc.cfg.SkipLocalLinks {
+			continue
+		}
+		fragment := ""
+		if lgc.cfg.CheckAnchors && u.Fragment != "" {
+			fragment = "#" + u.Fragment
+		}
+		if u.Path == "" && fragment == "" {
+			// a link to the same file such as "#foo" and "?foo"
+			continue
+		}
+		for _, loc := range locs {
+			p := filepath.Clean(loc.Path)
+			if u.Path != "" {
+				p = lgc.resolveLocalLink(loc.Path, u.Path)
+			}
+			if p == "" {
+				continue
+			}
+			links[p+fragment] = append(links[p+fragment], loc)
+		}
+	}
+	return links
+}
+
+// resolveLocalLink returns the path of the file linked by a relative link in the file src.
+func (lgc *logic) resolveLocalLink(src, link string) string {
+	if strings.HasPrefix(link, "/") {
+		if lgc.cfg.DocsRoot == "" {
+			return ""
+		}
+		return filepath.Join(lgc.cfg.DocsRoot, filepath.FromSlash(link))
+	}
+	return filepath.Join(filepath.Dir(src), filepath.FromSlash(link))
+}
+
+// checkLocalLink checks whether the linked local file or directory exists.
+// If check_anchors is true and the link has the fragment, whether the anchor exists in the Markdown or HTML file is checked.
+func (lgc *logic) checkLocalLink(link string) domain.Result {
+	result := domain.Result{
+		URL: link,
+	}
+	p, fragment := link, ""
+	if lgc.cfg.CheckAnchors {
+		if i := strings.Index(link, "#"); i != -1 {
+			p, fragment = link[:i], link[i+1:]
+		}
+	}
+	if !lgc.fsys.Exist(p) {
+		result.ErrorKind = domain.ErrorKindNotFound
+		result.Error = fmt.Sprintf("%s isn't found", p)
+		return result
+	}
+	if fragment == "" {
+		return result
+	}
+	anchors, ok, err := lgc.getLocalAnchors(p)
+	if err != nil {
+		result.ErrorKind = domain.ErrorKindOther
+		result.Error = fmt.Sprintf("failed to read %s: %s", p, err)
+		return result
+	}
+	if ok && !hasAnchor(anchors, fragment) {
+		result.ErrorKind = domain.ErrorKindAnchor
+		result.Error = fmt.Sprintf("the anchor #%s isn't found in %s", fragment, p)
+	}
+	return result
+}
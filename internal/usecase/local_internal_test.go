@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+	"github.com/suzuki-shunsuke/durl/internal/test"
+)
+
+func Test_logicExtractLocalLinks(t *testing.T) {
+	readme := domain.Location{Path: "README.md", Line: 1, Column: 1}
+	guide := domain.Location{Path: "docs/guide/index.md", Line: 2, Column: 3}
+	data := []struct {
+		title string
+		cfg   domain.Cfg
+		urls  map[string][]domain.Location
+		exp   map[string][]domain.Location
+		rest  map[string][]domain.Location
+	}{{
+		"normal", domain.Cfg{},
+		map[string][]domain.Location{
+			"https://github.com/suzuki-shunsuke/durl": {readme},
+			"mailto:foo@example.com":                  {readme},
+			"//example.com/foo":                       {readme},
+			"docs/guide/setup.md#install":             {readme},
+			"setup.md":                                {guide},
+			"../../images/arch%20v2.png?raw=true":     {guide},
+			"#usage":                                  {readme},
+			"/docs/guide/setup.md":                    {readme},
+		},
+		map[string][]domain.Location{
+			"docs/guide/setup.md": {readme, guide},
+			"images/arch v2.png":  {guide},
+		},
+		map[string][]domain.Location{
+			"https://github.com/suzuki-shunsuke/durl": {readme},
+			"mailto:foo@example.com":                  {readme},
+			"//example.com/foo":                       {readme},
+		},
+	}, {
+		"docs root", domain.Cfg{DocsRoot: "public"},
+		map[string][]domain.Location{
+			"/css/main.css": {readme},
+		},
+		map[string][]domain.Location{
+			"public/css/main.css": {readme},
+		},
+		map[string][]domain.Location{},
+	}, {
+		"skip local links", domain.Cfg{SkipLocalLinks: true},
+		map[string][]domain.Location{
+			"setup.md": {guide},
+		},
+		map[string][]domain.Location{},
+		map[string][]domain.Location{},
+	}}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			lgc := &logic{cfg: tt.cfg}
+			links := lgc.extractLocalLinks(tt.urls)
+			for _, locs := range links {
+				domain.SortLocations(locs)
+			}
+			require.Equal(t, tt.exp, links)
+			require.Equal(t, tt.rest, tt.urls)
+		})
+	}
+}
+
+func Test_logicCheckLocalLink(t *testing.T) {
+	fsys := test.NewFsys(t, nil).SetFuncExist(func(p string) bool {
+		return p == "docs/setup.md"
+	})
+	lgc := &logic{fsys: fsys}
+	result := lgc.CheckURL(context.Background(), "docs/setup.md")
+	require.False(t, result.Failed())
+	result = lgc.CheckURL(context.Background(), "docs/install.md")
+	require.Equal(t, domain.ErrorKindNotFound, result.ErrorKind)
+	require.Equal(t, "docs/install.md isn't found", result.Error)
+}
@@ -1,24 +1,41 @@
 package usecase
 
 import (
+	"net"
+
 	"github.com/suzuki-shunsuke/durl/internal/domain"
 )
 
 type (
 	logic struct {
-		logic  domain.Logic
-		cfg    domain.Cfg
-		fsys   domain.Fsys
-		client domain.HTTPClient
+		logic    domain.Logic
+		cfg      domain.Cfg
+		fsys     domain.Fsys
+		client   domain.HTTPClient
+		git      domain.Git
+		reporter domain.Reporter
+		// resolver resolves hosts to ignore urls by ignore_cidrs
+		resolver domain.Resolver
+		// pauses are shared by all requests to pause requests to hosts which return Retry-After
+		pauses hostPauses
+		// schedulers are shared by all requests to limit requests per host
+		schedulers hostSchedulers
+		// cache is read by Check. If it is nil, results aren't cached
+		cache *resultCache
 	}
 )
 
 // NewLogic returns a domain.Logic .
-func NewLogic(cfg domain.Cfg, fsys domain.Fsys, client domain.HTTPClient) domain.Logic {
+func NewLogic(
+	cfg domain.Cfg, fsys domain.Fsys, client domain.HTTPClient, git domain.Git, reporter domain.Reporter,
+) domain.Logic {
 	lgc := &logic{
-		cfg:    cfg,
-		fsys:   fsys,
-		client: client,
+		cfg:      cfg,
+		fsys:     fsys,
+		client:   client,
+		git:      git,
+		reporter: reporter,
+		resolver: net.DefaultResolver,
 	}
 	lgc.logic = lgc
 	return lgc
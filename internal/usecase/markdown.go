@@ -0,0 +1,231 @@
+package usecase
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"mvdan.cc/xurls/v2"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+var (
+	// codeFence matches the opening of a fenced code block such as "```go" and "~~~".
+	codeFence = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})(.*)$") //nolint:gochecknoglobals
+	// linkRefDef matches a link reference definition such as "[foo]: https://example.com".
+	linkRefDef = regexp.MustCompile(`^ {0,3}\[(?:[^\]\\]|\\.)+\]:[ \t]*(<[^<>\n]*>|\S+)`) //nolint:gochecknoglobals
+	// autolink matches an autolink such as "<https://example.com>".
+	autolink = regexp.MustCompile(`^<[A-Za-z][A-Za-z0-9+.\-]{1,31}:[^<>\x00-\x20]*>`) //nolint:gochecknoglobals
+)
+
+// markdownExtractor extracts urls from a Markdown document.
+type markdownExtractor struct {
+	path     string
+	skipCode bool
+	reg      *regexp.Regexp
+	links    []domain.Link
+	// col is the column of the start of the current text.
+	// It is larger than 1 if the text is a segment of a long line which is split by scanLines.
+	col int
+}
+
+// extractURLsFromMarkdown extracts urls of inline links, reference links, image links, autolinks and bare urls
+// from a Markdown document.
+// If skipCode is true, urls in fenced code blocks and code spans are skipped.
+// The document is read line by line, and a long line is processed per segment which is split by scanLines.
+func extractURLsFromMarkdown(r io.Reader, p string, skipCode bool) ([]domain.Link, error) {
+	ext := &markdownExtractor{
+		path:     p,
+		skipCode: skipCode,
+		reg:      xurls.Strict(),
+		links:    []domain.Link{},
+	}
+	// the fence of the current fenced code block. If the line isn't in a fenced code block, fence is empty
+	fence := ""
+	err := scanLines(r, func(line string, lineNum, col int) {
+		ext.col = col
+		if fence != "" {
+			if col == 1 && isClosingFence(line, fence) {
+				fence = ""
+				return
+			}
+			if !skipCode {
+				ext.extractBareURLs(line, lineNum)
+			}
+			return
+		}
+		// a fence is only at the start of a line
+		if m := codeFence.FindStringSubmatch(line); col == 1 && m != nil && !(m[1][0] == '`' && strings.Contains(m[2], "`")) {
+			fence = m[1]
+			return
+		}
+		ext.extractFromLine(line, lineNum)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ext.links, nil
+}
+
+// isClosingFence returns true if the line closes the fenced code block which is opened by the fence.
+func isClosingFence(line, fence string) bool {
+	s := strings.TrimLeft(line, " ")
+	if len(line)-len(s) > 3 {
+		return false
+	}
+	t := strings.TrimLeft(s, fence[:1])
+	return len(s)-len(t) >= len(fence) && strings.TrimSpace(t) == ""
+}
+
+func (ext *markdownExtractor) add(line string, lineNum, idx int, u string) {
+	ext.links = append(ext.links, domain.Link{
+		URL: u,
+		Location: domain.Location{
+			Path:   ext.path,
+			Line:   lineNum,
+			Column: ext.col + utf8.RuneCountInString(line[:idx]),
+		},
+	})
+}
+
+// extractBareURLs extracts urls which aren't written in the Markdown link syntax.
+func (ext *markdownExtractor) extractBareURLs(line string, lineNum int) {
+	for _, idx := range ext.reg.FindAllStringIndex(line, -1) {
+		ext.add(line, lineNum, idx[0], line[idx[0]:idx[1]])
+	}
+}
+
+// extractFromLine extracts urls from a line which isn't in a fenced code block.
+// Link destinations and autolinks are masked after they are extracted
+// so that they aren't extracted again as bare urls.
+func (ext *markdownExtractor) extractFromLine(line string, lineNum int) {
+	masked := []byte(line)
+	mask := func(start, end int) {
+		copy(masked[start:end], bytes.Repeat([]byte(" "), end-start))
+	}
+	// the index where inline elements are parsed from
+	pos := 0
+	if m := linkRefDef.FindStringSubmatchIndex(line); m != nil {
+		start, end := m[2], m[3]
+		if line[start] == '<' {
+			start++
+			end--
+		}
+		if start < end {
+			ext.add(line, lineNum, start, line[start:end])
+		}
+		mask(m[2], m[3])
+		pos = m[1]
+	}
+	for i := pos; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			// a backslash escape
+			i++
+		case '`':
+			// a code span is closed by the backtick string of the same length
+			n := countPrefix(line[i:], '`')
+			end := findBackticks(line, i+n, n)
+			if end == -1 {
+				i += n - 1
+				continue
+			}
+			if ext.skipCode {
+				mask(i, end+n)
+			}
+			// link syntax isn't parsed in a code span
+			i = end + n - 1
+		case '<':
+			loc := autolink.FindStringIndex(line[i:])
+			if loc == nil {
+				continue
+			}
+			ext.add(line, lineNum, i+1, line[i+1:i+loc[1]-1])
+			mask(i, i+loc[1])
+			i += loc[1] - 1
+		case ']':
+			if i+1 >= len(line) || line[i+1] != '(' {
+				continue
+			}
+			start, end, next := parseLinkDestination(line, i+2)
+			if start < end {
+				ext.add(line, lineNum, start, line[start:end])
+				mask(start, end)
+			}
+			i = next - 1
+		}
+	}
+	ext.extractBareURLs(string(masked), lineNum)
+}
+
+// parseLinkDestination parses the link destination of an inline link such as "[foo](https://example.com)".
+// i is the index of the character after "(".
+// start and end are the range of the destination and next is the index where the parsing is resumed.
+func parseLinkDestination(line string, i int) (start, end, next int) {
+	for i < len(line) && (line[i] == ' ' || line[i] == '\t') {
+		i++
+	}
+	if i < len(line) && line[i] == '<' {
+		// "<" destination ">"
+		j := strings.IndexAny(line[i+1:], "<>")
+		if j == -1 || line[i+1+j] != '>' {
+			return i, i, i
+		}
+		return i + 1, i + 1 + j, i + 2 + j
+	}
+	start = i
+	depth := 0
+	for ; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+			continue
+		case '(':
+			depth++
+			continue
+		case ')':
+			if depth > 0 {
+				depth--
+				continue
+			}
+		case ' ', '\t':
+		default:
+			continue
+		}
+		break
+	}
+	if i > len(line) {
+		i = len(line)
+	}
+	return start, i, i
+}
+
+// countPrefix returns the number of the leading c in s.
+func countPrefix(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+// findBackticks returns the index of the backtick string whose length is n, searching from the index i.
+// If it isn't found, -1 is returned.
+func findBackticks(line string, i, n int) int {
+	for i < len(line) {
+		j := strings.IndexByte(line[i:], '`')
+		if j == -1 {
+			return -1
+		}
+		i += j
+		m := countPrefix(line[i:], '`')
+		if m == n {
+			return i
+		}
+		i += m
+	}
+	return -1
+}
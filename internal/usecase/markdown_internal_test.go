@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+func Test_extractURLsFromMarkdown(t *testing.T) {
+	type link struct {
+		url  string
+		line int
+		col  int
+	}
+	doc := strings.Join([]string{
+		"# Title",
+		"[foo](https://example.com/foo) ![img](https://example.com/a.png \"title\")",
+		"see https://example.com/bare and <https://example.com/auto>.",
+		"[wiki](https://en.wikipedia.org/wiki/Go_(programming_language))",
+		"[bar][ref] [angle](<https://example.com/a b>)",
+		"",
+		"[ref]: https://example.com/ref",
+		"  [ref2]: <https://example.com/ref2> \"title\"",
+		"`https://example.com/span` \\[x](https://example.com/escaped)",
+		"```sh",
+		"curl https://example.com/fence",
+		"```",
+		"~~~",
+		"```",
+		"https://example.com/tilde",
+		"~~~",
+		"[あ](https://example.com/multibyte)",
+	}, "\n")
+	data := []struct {
+		title    string
+		skipCode bool
+		exp      []link
+	}{{
+		"normal", false, []link{
+			{"https://example.com/foo", 2, 7},
+			{"https://example.com/a.png", 2, 39},
+			{"https://example.com/auto", 3, 35},
+			{"https://example.com/bare", 3, 5},
+			{"https://en.wikipedia.org/wiki/Go_(programming_language)", 4, 8},
+			{"https://example.com/a b", 5, 21},
+			{"https://example.com/ref", 7, 8},
+			{"https://example.com/ref2", 8, 12},
+			{"https://example.com/escaped", 9, 33},
+			{"https://example.com/span", 9, 2},
+			{"https://example.com/fence", 11, 6},
+			{"https://example.com/tilde", 15, 1},
+			{"https://example.com/multibyte", 17, 5},
+		},
+	}, {
+		"skip code", true, []link{
+			{"https://example.com/foo", 2, 7},
+			{"https://example.com/a.png", 2, 39},
+			{"https://example.com/auto", 3, 35},
+			{"https://example.com/bare", 3, 5},
+			{"https://en.wikipedia.org/wiki/Go_(programming_language)", 4, 8},
+			{"https://example.com/a b", 5, 21},
+			{"https://example.com/ref", 7, 8},
+			{"https://example.com/ref2", 8, 12},
+			{"https://example.com/escaped", 9, 33},
+			{"https://example.com/multibyte", 17, 5},
+		},
+	}}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			links, err := extractURLsFromMarkdown(strings.NewReader(doc), "README.md", tt.skipCode)
+			require.Nil(t, err)
+			exp := make([]domain.Link, len(tt.exp))
+			for i, l := range tt.exp {
+				exp[i] = domain.Link{
+					URL:      l.url,
+					Location: domain.Location{Path: "README.md", Line: l.line, Column: l.col},
+				}
+			}
+			require.Equal(t, exp, links)
+		})
+	}
+}
+
+func Test_logicExtractURLsMarkdown(t *testing.T) {
+	// the extractor is selected by the file extension
+	in := "```\nhttps://example.com/foo\n```\n"
+	lgc := &logic{cfg: domain.Cfg{MarkdownSkipCode: true}}
+	links, err := lgc.extractURLs(strings.NewReader(in), "docs/README.MD")
+	require.Nil(t, err)
+	require.Empty(t, links)
+	links, err = lgc.extractURLs(strings.NewReader(in), "main.go")
+	require.Nil(t, err)
+	require.Equal(t, []domain.Link{{
+		URL:      "https://example.com/foo",
+		Location: domain.Location{Path: "main.go", Line: 2, Column: 1},
+	}}, links)
+}
+
+func Test_extractURLsFromMarkdownLongLine(t *testing.T) {
+	// a line longer than the chunk of scanLines is processed per segment
+	doc := "# Title\n" + strings.Repeat("あ ", maxChunkSize) + "[foo](https://example.com/foo)\nhttps://example.com/bar\n"
+	links, err := extractURLsFromMarkdown(strings.NewReader(doc), "README.md", false)
+	require.Nil(t, err)
+	require.Equal(t, []domain.Link{{
+		URL:      "https://example.com/foo",
+		Location: domain.Location{Path: "README.md", Line: 2, Column: maxChunkSize*2 + 7},
+	}, {
+		URL:      "https://example.com/bar",
+		Location: domain.Location{Path: "README.md", Line: 3, Column: 1},
+	}}, links)
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// NewCheckRedirect returns a function for http.Client.CheckRedirect which stops following redirects
+// after maxRedirects redirects. The last redirect response is returned as the response.
+func NewCheckRedirect(maxRedirects int) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			return http.ErrUseLastResponse
+		}
+		return nil
+	}
+}
+
+// isPermanentRedirect returns true if the status code is 301 Moved Permanently or 308 Permanent Redirect.
+func isPermanentRedirect(statusCode int) bool {
+	return statusCode == http.StatusMovedPermanently || statusCode == http.StatusPermanentRedirect
+}
+
+// getSuggestedURL returns the url which the url is permanently redirected to.
+// Permanent redirects are followed from the first redirect until a temporary redirect such as 302 and 307.
+// If the first redirect isn't permanent, an empty string is returned.
+func getSuggestedURL(redirects []domain.Redirect) string {
+	u := ""
+	for _, redirect := range redirects {
+		if !isPermanentRedirect(redirect.StatusCode) {
+			break
+		}
+		u = redirect.URL
+	}
+	return u
+}
+
+// applyRedirectPolicy sets a warning or an error to the result according to the redirects policy
+// if the url is permanently redirected.
+func (lgc *logic) applyRedirectPolicy(result *domain.Result) {
+	if result.Failed() {
+		return
+	}
+	result.SuggestedURL = getSuggestedURL(result.Redirects)
+	if result.SuggestedURL == "" {
+		return
+	}
+	// the fragment isn't sent to the server, so it is inherited as browsers do
+	if i := strings.Index(result.URL, "#"); i != -1 && !strings.Contains(result.SuggestedURL, "#") {
+		result.SuggestedURL += result.URL[i:]
+	}
+	msg := fmt.Sprintf("%s is permanently redirected to %s", result.URL, result.SuggestedURL)
+	switch lgc.cfg.Redirects {
+	case domain.RedirectsWarn:
+		result.Warning = msg
+	case domain.RedirectsError:
+		result.ErrorKind = domain.ErrorKindRedirect
+		result.Error = msg
+	}
+}
+
+// initRedirects validates the settings of redirects and sets the default values.
+func initRedirects(cfg domain.Cfg) (domain.Cfg, error) {
+	switch cfg.Redirects {
+	case "":
+		cfg.Redirects = domain.RedirectsFollow
+	case domain.RedirectsFollow, domain.RedirectsWarn, domain.RedirectsError:
+	default:
+		return cfg, fmt.Errorf(`invalid redirects: %s`, cfg.Redirects)
+	}
+	if cfg.MaxRedirects < 0 {
+		return cfg, fmt.Errorf(`max_redirects must not be negative: %d`, cfg.MaxRedirects)
+	}
+	if cfg.MaxRedirects == 0 {
+		cfg.MaxRedirects = domain.DefaultMaxRedirects
+	}
+	return cfg, nil
+}
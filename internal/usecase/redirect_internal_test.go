@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+func Test_getSuggestedURL(t *testing.T) {
+	data := []struct {
+		title     string
+		redirects []domain.Redirect
+		exp       string
+	}{
+		{"no redirect", nil, ""},
+		{"temporary", []domain.Redirect{{StatusCode: 302, URL: "http://example.com/b"}}, ""},
+		{"permanent", []domain.Redirect{
+			{StatusCode: 301, URL: "http://example.com/b"},
+			{StatusCode: 308, URL: "http://example.com/c"},
+		}, "http://example.com/c"},
+		{"permanent and temporary", []domain.Redirect{
+			{StatusCode: 301, URL: "http://example.com/b"},
+			{StatusCode: 307, URL: "http://example.com/c"},
+			{StatusCode: 301, URL: "http://example.com/d"},
+		}, "http://example.com/b"},
+	}
+	for _, tt := range data {
+		require.Equal(t, tt.exp, getSuggestedURL(tt.redirects), tt.title)
+	}
+}
+
+func Test_logicCheckURLWithMethodRedirectPolicy(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/old", http.RedirectHandler("/new", http.StatusMovedPermanently))
+	mux.Handle("/temporary", http.RedirectHandler("/new", http.StatusFound))
+	mux.Handle("/loop", http.RedirectHandler("/loop", http.StatusFound))
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	data := []struct {
+		title     string
+		path      string
+		redirects string
+		kind      string
+		suggested string
+		warned    bool
+	}{
+		{"follow", "/old", domain.RedirectsFollow, "", "/new", false},
+		{"warn", "/old", domain.RedirectsWarn, "", "/new", true},
+		{"fragment", "/old#usage", domain.RedirectsFollow, "", "/new#usage", false},
+		{"error", "/old", domain.RedirectsError, domain.ErrorKindRedirect, "/new", false},
+		{"temporary redirect", "/temporary", domain.RedirectsError, "", "", false},
+		{"too many redirects", "/loop", domain.RedirectsFollow, domain.ErrorKindRedirect, "", false},
+	}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			lgc := &logic{
+				cfg: domain.Cfg{Redirects: tt.redirects, MaxRedirects: 3},
+				client: &http.Client{
+					CheckRedirect: NewCheckRedirect(3),
+				},
+			}
+			result := lgc.CheckURLWithMethod(context.Background(), server.URL+tt.path, http.MethodGet)
+			require.Equal(t, tt.kind, result.ErrorKind, result.Error)
+			if tt.suggested != "" {
+				require.Equal(t, server.URL+tt.suggested, result.SuggestedURL)
+			} else {
+				require.Empty(t, result.SuggestedURL)
+			}
+			require.Equal(t, tt.warned, result.Warning != "")
+			if tt.path == "/loop" {
+				require.Len(t, result.Redirects, 3)
+			}
+		})
+	}
+}
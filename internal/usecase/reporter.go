@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+type (
+	textReporter struct {
+		w io.Writer
+	}
+)
+
+// NewTextReporter returns a domain.Reporter which outputs dead urls and warnings as text.
+func NewTextReporter(w io.Writer) domain.Reporter {
+	return &textReporter{w: w}
+}
+
+func (reporter *textReporter) Report(result domain.Result) error {
+	if !result.Failed() && result.Warning == "" {
+		return nil
+	}
+	locs := make([]string, len(result.Locations))
+	for i, loc := range result.Locations {
+		locs[i] = loc.String()
+	}
+	if !result.Failed() {
+		_, err := fmt.Fprintf(
+			reporter.w, "[WARN] %s [%s]: %s\n",
+			result.URL, strings.Join(locs, ", "), result.Warning)
+		return err
+	}
+	_, err := fmt.Fprintf(
+		reporter.w, "failed to check a url %s [%s]: %s\n",
+		result.URL, strings.Join(locs, ", "), result.Error)
+	return err
+}
+
+func (reporter *textReporter) Finish(results []domain.Result) error {
+	return nil
+}
+
+// NewReporter returns a domain.Reporter of the output format.
+// Dead urls are output to stderr as text, and the other formats are output to stdout.
+func NewReporter(format string, stdout, stderr io.Writer) (domain.Reporter, error) {
+	switch format {
+	case domain.FormatText, "":
+		return NewTextReporter(stderr), nil
+	case domain.FormatJSON:
+		return NewJSONReporter(stdout), nil
+	case domain.FormatNDJSON:
+		return NewNDJSONReporter(stdout), nil
+	case domain.FormatSARIF:
+		return NewSARIFReporter(stdout), nil
+	case domain.FormatJUnit:
+		return NewJUnitReporter(stdout), nil
+	default:
+		return nil, fmt.Errorf("invalid format: %s", format)
+	}
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+func Test_textReporter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	reporter := NewTextReporter(buf)
+	results := []domain.Result{{
+		URL: "https://example.com/foo", Locations: []domain.Location{{Path: "foo.txt"}}, StatusCode: 200,
+	}, {
+		URL: "https://example.com/bar", Locations: []domain.Location{{Path: "bar.txt", Line: 2, Column: 3}, {Path: "foo.txt"}}, StatusCode: 404,
+		ErrorKind: domain.ErrorKindHTTPClientError, Error: "https://example.com/bar is dead (404)",
+	}, {
+		URL: "https://example.com/old", Locations: []domain.Location{{Path: "foo.txt"}}, StatusCode: 200,
+		SuggestedURL: "https://example.com/new", Warning: "https://example.com/old is permanently redirected to https://example.com/new",
+	}}
+	for _, result := range results {
+		require.Nil(t, reporter.Report(result))
+	}
+	require.Nil(t, reporter.Finish(results))
+	require.Equal(t, `failed to check a url https://example.com/bar [bar.txt:2:3, foo.txt]: https://example.com/bar is dead (404)
+[WARN] https://example.com/old [foo.txt]: https://example.com/old is permanently redirected to https://example.com/new
+`, buf.String())
+}
+
+func TestNewReporter(t *testing.T) {
+	for _, format := range []string{"", domain.FormatText, domain.FormatJSON, domain.FormatNDJSON} {
+		reporter, err := NewReporter(format, nil, nil)
+		require.Nil(t, err, format)
+		require.NotNil(t, reporter, format)
+	}
+	_, err := NewReporter("xml", nil, nil)
+	require.NotNil(t, err)
+}
+
+func Test_jsonReporter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	reporter := NewJSONReporter(buf)
+	results := []domain.Result{{
+		URL: "https://example.com/foo", Locations: []domain.Location{{Path: "foo.txt"}}, Method: "HEAD", StatusCode: 200,
+		Duration:  1500 * time.Millisecond,
+		Redirects: []domain.Redirect{{StatusCode: 301, URL: "https://example.com/foo/"}},
+	}, {
+		URL: "https://example.com/bar", Locations: []domain.Location{{Path: "bar.txt", Line: 2, Column: 3}, {Path: "foo.html", Line: 1, Column: 10, Context: "a[href]"}}, Method: "GET", StatusCode: 404,
+		ErrorKind: domain.ErrorKindHTTPClientError, Error: "https://example.com/bar is dead (404)",
+	}}
+	for _, result := range results {
+		require.Nil(t, reporter.Report(result))
+	}
+	require.Equal(t, "", buf.String())
+	require.Nil(t, reporter.Finish(results))
+	require.JSONEq(t, `{
+  "summary": {"total": 2, "ok": 1, "failed": 1},
+  "results": [{
+    "url": "https://example.com/bar",
+    "status": "failed",
+    "locations": [{"path": "bar.txt", "line": 2, "column": 3}, {"path": "foo.html", "line": 1, "column": 10, "context": "a[href]"}],
+    "method": "GET",
+    "status_code": 404,
+    "error_kind": "http_4xx",
+    "error": "https://example.com/bar is dead (404)",
+    "duration_ms": 0
+  }, {
+    "url": "https://example.com/foo",
+    "status": "ok",
+    "locations": [{"path": "foo.txt"}],
+    "method": "HEAD",
+    "status_code": 200,
+    "duration_ms": 1500,
+    "redirects": [{"status_code": 301, "url": "https://example.com/foo/"}]
+  }]
+}`, buf.String())
+}
+
+func Test_ndjsonReporter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	reporter := NewNDJSONReporter(buf)
+	results := []domain.Result{{
+		URL: "https://example.com/foo", Locations: []domain.Location{{Path: "foo.txt"}}, Method: "HEAD", StatusCode: 200,
+	}}
+	require.Nil(t, reporter.Report(results[0]))
+	require.Equal(t, `{"type":"result","url":"https://example.com/foo","status":"ok","locations":[{"path":"foo.txt"}],"method":"HEAD","status_code":200,"duration_ms":0}
+`, buf.String())
+	buf.Reset()
+	require.Nil(t, reporter.Finish(results))
+	require.Equal(t, `{"type":"summary","total":1,"ok":1,"failed":0}
+`, buf.String())
+}
+
+func Test_sarifReporter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	reporter := NewSARIFReporter(buf)
+	results := []domain.Result{{
+		URL: "https://example.com/foo", Locations: []domain.Location{{Path: "foo.txt"}}, StatusCode: 200,
+	}, {
+		URL: "https://example.com/bar", Locations: []domain.Location{{Path: "bar.txt", Line: 2, Column: 3}, {Path: "./docs/foo.txt"}},
+		ErrorKind: domain.ErrorKindTimeout, Error: "timeout",
+	}, {
+		URL: "https://example.com/old", Locations: []domain.Location{{Path: "foo.txt"}}, StatusCode: 200,
+		SuggestedURL: "https://example.com/new", Warning: "permanently redirected",
+	}}
+	require.Nil(t, reporter.Report(results[0]))
+	require.Nil(t, reporter.Finish(results))
+	log := sarifLog{}
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &log))
+	require.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	require.Equal(t, []sarifResult{{
+		RuleID: domain.ErrorKindTimeout, RuleIndex: 5, Level: "error", Message: sarifMessage{"timeout"},
+		Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{
+			ArtifactLocation: sarifArtifactLocation{URI: "bar.txt"},
+			Region:           &sarifRegion{StartLine: 2, StartColumn: 3, EndColumn: 26},
+		}}},
+	}, {
+		RuleID: domain.ErrorKindTimeout, RuleIndex: 5, Level: "error", Message: sarifMessage{"timeout"},
+		Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "docs/foo.txt"}}}},
+	}, {
+		RuleID: domain.ErrorKindRedirect, RuleIndex: getSARIFRuleIndex(domain.ErrorKindRedirect), Level: "warning",
+		Message:   sarifMessage{"permanently redirected"},
+		Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "foo.txt"}}}},
+	}}, log.Runs[0].Results)
+}
+
+func Test_junitReporter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	reporter := NewJUnitReporter(buf)
+	results := []domain.Result{{
+		URL: "https://example.com/foo", Locations: []domain.Location{{Path: "foo.txt"}}, StatusCode: 200,
+		Duration: 1500 * time.Millisecond,
+	}, {
+		URL: "https://example.com/bar", StatusCode: 404,
+		Locations: []domain.Location{
+			{Path: "bar.txt"}, {Path: "foo.txt", Line: 3, Column: 5}, {Path: "foo.txt", Line: 10, Column: 1},
+		},
+		ErrorKind: domain.ErrorKindHTTPClientError, Error: "https://example.com/bar is dead (404)",
+	}}
+	require.Nil(t, reporter.Report(results[0]))
+	require.Nil(t, reporter.Finish(results))
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="durl" tests="3" failures="2">
+  <testsuite name="bar.txt" tests="1" failures="1" time="0.000">
+    <testcase name="https://example.com/bar" classname="bar.txt" time="0.000">
+      <failure message="https://example.com/bar is dead (404)" type="http_4xx"><![CDATA[https://example.com/bar is dead (404)]]></failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="foo.txt" tests="2" failures="1" time="1.500">
+    <testcase name="https://example.com/bar" classname="foo.txt" time="0.000">
+      <failure message="https://example.com/bar is dead (404)" type="http_4xx"><![CDATA[https://example.com/bar is dead (404)
+foo.txt:3:5
+foo.txt:10:1]]></failure>
+    </testcase>
+    <testcase name="https://example.com/foo" classname="foo.txt" time="1.500"></testcase>
+  </testsuite>
+</testsuites>
+`, buf.String())
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+type (
+	jsonReporter struct {
+		w io.Writer
+	}
+
+	ndjsonReporter struct {
+		encoder *json.Encoder
+	}
+
+	jsonDocument struct {
+		Summary jsonSummary  `json:"summary"`
+		Results []jsonResult `json:"results"`
+	}
+
+	jsonSummary struct {
+		Type   string `json:"type,omitempty"`
+		Total  int    `json:"total"`
+		OK     int    `json:"ok"`
+		Failed int    `json:"failed"`
+	}
+
+	jsonResult struct {
+		Type       string         `json:"type,omitempty"`
+		URL        string         `json:"url"`
+		Status     string         `json:"status"`
+		Locations  []jsonLocation `json:"locations"`
+		Method     string         `json:"method,omitempty"`
+		StatusCode int            `json:"status_code,omitempty"`
+		ErrorKind  string         `json:"error_kind,omitempty"`
+		Error      string         `json:"error,omitempty"`
+		DurationMS int64          `json:"duration_ms"`
+		Redirects  []jsonRedirect `json:"redirects,omitempty"`
+		Attempts   int            `json:"attempts,omitempty"`
+		// SuggestedURL is the url which the url is permanently redirected to
+		SuggestedURL string `json:"suggested_url,omitempty"`
+		Warning      string `json:"warning,omitempty"`
+		Cached       bool   `json:"cached,omitempty"`
+	}
+
+	jsonLocation struct {
+		Path    string `json:"path"`
+		Line    int    `json:"line,omitempty"`
+		Column  int    `json:"column,omitempty"`
+		Context string `json:"context,omitempty"`
+	}
+
+	jsonRedirect struct {
+		StatusCode int    `json:"status_code"`
+		URL        string `json:"url"`
+	}
+)
+
+const (
+	jsonStatusOK     = "ok"
+	jsonStatusFailed = "failed"
+)
+
+// NewJSONReporter returns a domain.Reporter which outputs all results as a JSON document after all urls are checked.
+func NewJSONReporter(w io.Writer) domain.Reporter {
+	return &jsonReporter{w: w}
+}
+
+// NewNDJSONReporter returns a domain.Reporter which outputs a JSON line whenever a url is checked.
+func NewNDJSONReporter(w io.Writer) domain.Reporter {
+	return &ndjsonReporter{encoder: json.NewEncoder(w)}
+}
+
+func (reporter *jsonReporter) Report(result domain.Result) error {
+	return nil
+}
+
+func (reporter *jsonReporter) Finish(results []domain.Result) error {
+	doc := jsonDocument{
+		Summary: newJSONSummary(results),
+		Results: make([]jsonResult, len(results)),
+	}
+	for i, result := range results {
+		doc.Results[i] = newJSONResult(result)
+	}
+	sort.Slice(doc.Results, func(i, j int) bool {
+		return doc.Results[i].URL < doc.Results[j].URL
+	})
+	encoder := json.NewEncoder(reporter.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+func (reporter *ndjsonReporter) Report(result domain.Result) error {
+	r := newJSONResult(result)
+	r.Type = "result"
+	return reporter.encoder.Encode(r)
+}
+
+func (reporter *ndjsonReporter) Finish(results []domain.Result) error {
+	summary := newJSONSummary(results)
+	summary.Type = "summary"
+	return reporter.encoder.Encode(summary)
+}
+
+func newJSONSummary(results []domain.Result) jsonSummary {
+	summary := jsonSummary{Total: len(results)}
+	for _, result := range results {
+		if result.Failed() {
+			summary.Failed++
+			continue
+		}
+		summary.OK++
+	}
+	return summary
+}
+
+func newJSONResult(result domain.Result) jsonResult {
+	r := jsonResult{
+		URL:        result.URL,
+		Status:     jsonStatusOK,
+		Locations:  make([]jsonLocation, len(result.Locations)),
+		Method:     result.Method,
+		StatusCode: result.StatusCode,
+		ErrorKind:  result.ErrorKind,
+		Error:      result.Error,
+		DurationMS: result.Duration.Milliseconds(),
+		Attempts:   result.Attempts,
+
+		SuggestedURL: result.SuggestedURL,
+		Warning:      result.Warning,
+		Cached:       result.Cached,
+	}
+	if result.Failed() {
+		r.Status = jsonStatusFailed
+	}
+	for i, loc := range result.Locations {
+		r.Locations[i] = jsonLocation{
+			Path:    loc.Path,
+			Line:    loc.Line,
+			Column:  loc.Column,
+			Context: loc.Context,
+		}
+	}
+	for _, redirect := range result.Redirects {
+		r.Redirects = append(r.Redirects, jsonRedirect{
+			StatusCode: redirect.StatusCode,
+			URL:        redirect.URL,
+		})
+	}
+	return r
+}
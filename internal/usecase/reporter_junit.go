@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+type (
+	junitReporter struct {
+		w io.Writer
+	}
+
+	junitTestSuites struct {
+		XMLName    xml.Name         `xml:"testsuites"`
+		Name       string           `xml:"name,attr"`
+		Tests      int              `xml:"tests,attr"`
+		Failures   int              `xml:"failures,attr"`
+		TestSuites []junitTestSuite `xml:"testsuite"`
+	}
+
+	junitTestSuite struct {
+		Name      string          `xml:"name,attr"`
+		Tests     int             `xml:"tests,attr"`
+		Failures  int             `xml:"failures,attr"`
+		Time      string          `xml:"time,attr"`
+		TestCases []junitTestCase `xml:"testcase"`
+	}
+
+	junitTestCase struct {
+		Name      string        `xml:"name,attr"`
+		ClassName string        `xml:"classname,attr"`
+		Time      string        `xml:"time,attr"`
+		Failure   *junitFailure `xml:"failure,omitempty"`
+	}
+
+	junitFailure struct {
+		Message string `xml:"message,attr"`
+		Type    string `xml:"type,attr"`
+		Text    string `xml:",cdata"`
+	}
+)
+
+// NewJUnitReporter returns a domain.Reporter which outputs all results as a JUnit XML report after all urls are checked.
+// A testsuite is output per a file and a testcase is output per a url in the file.
+func NewJUnitReporter(w io.Writer) domain.Reporter {
+	return &junitReporter{w: w}
+}
+
+func (reporter *junitReporter) Report(result domain.Result) error {
+	return nil
+}
+
+func (reporter *junitReporter) Finish(results []domain.Result) error {
+	// file path -> testsuite
+	suites := map[string]*junitTestSuite{}
+	durations := map[string]float64{}
+	for _, result := range results {
+		for _, p := range result.Files() {
+			suite, ok := suites[p]
+			if !ok {
+				suite = &junitTestSuite{Name: p}
+				suites[p] = suite
+			}
+			testCase := junitTestCase{
+				Name:      result.URL,
+				ClassName: p,
+				Time:      formatJUnitTime(result.Duration.Seconds()),
+			}
+			if result.Failed() {
+				testCase.Failure = &junitFailure{
+					Message: getJUnitFailureMessage(result),
+					Type:    result.ErrorKind,
+					Text:    getJUnitFailureText(result, p),
+				}
+				suite.Failures++
+			}
+			suite.Tests++
+			durations[p] += result.Duration.Seconds()
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+	}
+	root := junitTestSuites{
+		Name:       "durl",
+		TestSuites: make([]junitTestSuite, 0, len(suites)),
+	}
+	for p, suite := range suites {
+		sort.Slice(suite.TestCases, func(i, j int) bool {
+			return suite.TestCases[i].Name < suite.TestCases[j].Name
+		})
+		suite.Time = formatJUnitTime(durations[p])
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.TestSuites = append(root.TestSuites, *suite)
+	}
+	sort.Slice(root.TestSuites, func(i, j int) bool {
+		return root.TestSuites[i].Name < root.TestSuites[j].Name
+	})
+	if _, err := io.WriteString(reporter.w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(reporter.w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(reporter.w, "\n")
+	return err
+}
+
+func getJUnitFailureMessage(result domain.Result) string {
+	if result.StatusCode != 0 {
+		return fmt.Sprintf("%s is dead (%d)", result.URL, result.StatusCode)
+	}
+	return fmt.Sprintf("%s is dead (%s)", result.URL, result.ErrorKind)
+}
+
+// getJUnitFailureText returns the error message and locations of the url in the file.
+func getJUnitFailureText(result domain.Result, p string) string {
+	lines := []string{result.Error}
+	for _, loc := range result.Locations {
+		if loc.Path == p && loc.Line != 0 {
+			lines = append(lines, loc.String())
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatJUnitTime(sec float64) string {
+	return fmt.Sprintf("%.3f", sec)
+}
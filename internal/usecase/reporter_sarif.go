@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+type (
+	sarifReporter struct {
+		w io.Writer
+	}
+
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+
+	sarifRun struct {
+		Tool       sarifTool     `json:"tool"`
+		Results    []sarifResult `json:"results"`
+		ColumnKind string        `json:"columnKind"`
+	}
+
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		Version        string      `json:"version"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+
+	sarifRule struct {
+		ID               string       `json:"id"`
+		ShortDescription sarifMessage `json:"shortDescription"`
+	}
+
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+
+	sarifResult struct {
+		RuleID    string          `json:"ruleId"`
+		RuleIndex int             `json:"ruleIndex"`
+		Level     string          `json:"level"`
+		Message   sarifMessage    `json:"message"`
+		Locations []sarifLocation `json:"locations"`
+	}
+
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           *sarifRegion          `json:"region,omitempty"`
+	}
+
+	sarifRegion struct {
+		StartLine   int `json:"startLine"`
+		StartColumn int `json:"startColumn"`
+		EndColumn   int `json:"endColumn"`
+	}
+
+	sarifArtifactLocation struct {
+		URI string `json:"uri"`
+	}
+)
+
+// sarifRules are rules of SARIF. A rule corresponds to an error kind.
+var sarifRules = []sarifRule{ //nolint:gochecknoglobals
+	{domain.ErrorKindHTTPClientError, sarifMessage{"The url returns a 4xx HTTP status code"}},
+	{domain.ErrorKindHTTPServerError, sarifMessage{"The url returns a 5xx HTTP status code"}},
+	{domain.ErrorKindHTTPStatus, sarifMessage{"The url returns an unexpected HTTP status code"}},
+	{domain.ErrorKindDNS, sarifMessage{"The host of the url can't be resolved"}},
+	{domain.ErrorKindTLS, sarifMessage{"The TLS connection to the url can't be established"}},
+	{domain.ErrorKindTimeout, sarifMessage{"The request to the url is timed out"}},
+	{domain.ErrorKindConnection, sarifMessage{"The connection to the url is failed"}},
+	{domain.ErrorKindRedirect, sarifMessage{"The url is permanently redirected or redirected too many times"}},
+	{domain.ErrorKindNotFound, sarifMessage{"The linked local file or directory doesn't exist"}},
+	{domain.ErrorKindAnchor, sarifMessage{"The anchor of the url isn't found in the page"}},
+	{domain.ErrorKindInvalid, sarifMessage{"The url is invalid"}},
+	{domain.ErrorKindOther, sarifMessage{"The url can't be checked"}},
+}
+
+// NewSARIFReporter returns a domain.Reporter which outputs dead urls as a SARIF 2.1.0 log after all urls are checked.
+func NewSARIFReporter(w io.Writer) domain.Reporter {
+	return &sarifReporter{w: w}
+}
+
+func (reporter *sarifReporter) Report(result domain.Result) error {
+	return nil
+}
+
+func (reporter *sarifReporter) Finish(results []domain.Result) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "durl",
+				Version:        domain.Version,
+				InformationURI: "https://github.com/suzuki-shunsuke/durl",
+				Rules:          sarifRules,
+			},
+		},
+		Results: []sarifResult{},
+		// Location.Column is counted in characters
+		ColumnKind: "unicodeCodePoints",
+	}
+	sorted := make([]domain.Result, len(results))
+	copy(sorted, results)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].URL < sorted[j].URL
+	})
+	for _, result := range sorted {
+		level, kind, msg := "error", result.ErrorKind, result.Error
+		if !result.Failed() {
+			if result.Warning == "" {
+				continue
+			}
+			// a permanent redirect
+			level, kind, msg = "warning", domain.ErrorKindRedirect, result.Warning
+		}
+		ruleIndex := getSARIFRuleIndex(kind)
+		// a SARIF result per a location
+		for _, loc := range result.Locations {
+			physicalLocation := sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: getSARIFURI(loc.Path)},
+			}
+			if loc.Line != 0 {
+				physicalLocation.Region = &sarifRegion{
+					StartLine:   loc.Line,
+					StartColumn: loc.Column,
+					EndColumn:   loc.Column + utf8.RuneCountInString(result.URL),
+				}
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    sarifRules[ruleIndex].ID,
+				RuleIndex: ruleIndex,
+				Level:     level,
+				Message:   sarifMessage{Text: msg},
+				Locations: []sarifLocation{{PhysicalLocation: physicalLocation}},
+			})
+		}
+	}
+	encoder := json.NewEncoder(reporter.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+func getSARIFRuleIndex(kind string) int {
+	for i, rule := range sarifRules {
+		if rule.ID == kind {
+			return i
+		}
+	}
+	return len(sarifRules) - 1
+}
+
+// getSARIFURI converts a file path to the uri of the artifact location.
+func getSARIFURI(p string) string {
+	if filepath.IsAbs(p) {
+		return "file://" + filepath.ToSlash(p)
+	}
+	return cleanPath(p)
+}
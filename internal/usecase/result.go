@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// getStatusErrorKind returns the error kind of the HTTP status code which isn't accepted.
+func getStatusErrorKind(statusCode int) string {
+	switch statusCode / 100 { //nolint:gomnd
+	case 4: //nolint:gomnd
+		return domain.ErrorKindHTTPClientError
+	case 5: //nolint:gomnd
+		return domain.ErrorKindHTTPServerError
+	default:
+		return domain.ErrorKindHTTPStatus
+	}
+}
+
+// getErrorKind returns the error kind of the error of the HTTP request.
+func getErrorKind(err error) string {
+	var (
+		netErr       net.Error
+		opErr        *net.OpError
+		dnsErr       *net.DNSError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		certErr      x509.CertificateInvalidError
+	)
+	switch {
+	case errors.As(err, &dnsErr):
+		return domain.ErrorKindDNS
+	case errors.Is(err, context.DeadlineExceeded):
+		return domain.ErrorKindTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return domain.ErrorKindTimeout
+	case errors.As(err, &authorityErr), errors.As(err, &hostnameErr), errors.As(err, &certErr):
+		return domain.ErrorKindTLS
+	case strings.Contains(err.Error(), "tls: "), strings.Contains(err.Error(), "x509: "):
+		return domain.ErrorKindTLS
+	case errors.As(err, &opErr), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return domain.ErrorKindConnection
+	default:
+		return domain.ErrorKindOther
+	}
+}
+
+// getRedirects returns redirects which are followed to get the response.
+func getRedirects(resp *http.Response) []domain.Redirect {
+	var redirects []domain.Redirect
+	for req := resp.Request; req != nil && req.Response != nil; req = req.Response.Request {
+		redirects = append([]domain.Redirect{{
+			StatusCode: req.Response.StatusCode,
+			URL:        req.URL.String(),
+		}}, redirects...)
+	}
+	return redirects
+}
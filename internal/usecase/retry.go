@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// requestWithRetry sends a HTTP request and retries it when the request fails transiently.
+// If the host returns 429 or 503 with the Retry-After header, requests to the host are paused for the period
+// and the request is retried regardless of retry_count,
+// as long as the total wait for the url doesn't exceed retry_after_max_wait.
+// The response body of the last attempt must be closed by the caller.
+func (lgc *logic) requestWithRetry(ctx context.Context, u, method string) (domain.Result, *http.Response) {
+	host := getHost(u)
+	// the total wait for Retry-After
+	waited := time.Duration(0)
+	retries := 0
+	for attempt := 1; ; attempt++ {
+		d, ok := lgc.waitHost(ctx, host, lgc.cfg.RetryAfterMaxWait-waited)
+		if ok {
+			waited += d
+			// per_host_requests_per_second
+			ok = lgc.getHostScheduler(host).wait(ctx)
+		}
+		if !ok {
+			return domain.Result{
+				URL: u, Method: method, Attempts: attempt - 1,
+				ErrorKind: domain.ErrorKindTimeout, Error: ctx.Err().Error(),
+			}, nil
+		}
+		result, resp := lgc.request(ctx, u, method)
+		result.Attempts = attempt
+		if d, ok := getRetryAfter(resp); ok && result.Failed() && waited+d <= lgc.cfg.RetryAfterMaxWait {
+			lgc.pauses.pause(host, d)
+			resp.Body.Close()
+			continue
+		}
+		if retries >= lgc.cfg.RetryCount || !isTransientFailure(result) {
+			return result, resp
+		}
+		retries++
+		if resp != nil {
+			resp.Body.Close()
+		}
+		// the slot of max_request_count is released during the backoff
+		if !sleepWithoutGlobalSlot(ctx, lgc.getRetryDelay(retries)) {
+			return result, nil
+		}
+	}
+}
+
+// isTransientFailure returns true if the failure can be recovered by retrying the request.
+func isTransientFailure(result domain.Result) bool {
+	if !result.Failed() {
+		return false
+	}
+	if result.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	switch result.ErrorKind {
+	case domain.ErrorKindTimeout, domain.ErrorKindConnection, domain.ErrorKindHTTPServerError:
+		return true
+	default:
+		return false
+	}
+}
+
+// getRetryDelay returns the delay before the next attempt.
+// The delay is doubled per attempt up to retry_max_delay, and the half of the delay is randomized as jitter.
+func (lgc *logic) getRetryDelay(attempt int) time.Duration {
+	delay := lgc.cfg.RetryBackoff
+	for i := 1; i < attempt; i++ {
+		if delay >= lgc.cfg.RetryMaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	if delay > lgc.cfg.RetryMaxDelay {
+		delay = lgc.cfg.RetryMaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1)) //nolint:gosec
+}
+
+// sleep waits for the duration. If the context is canceled, false is returned.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// initRetry validates the settings of retries and sets the default values.
+func initRetry(cfg domain.Cfg) (domain.Cfg, error) {
+	if cfg.RetryCount < 0 {
+		return cfg, fmt.Errorf(`retry_count must not be negative: %d`, cfg.RetryCount)
+	}
+	if cfg.RetryBackoff == 0 {
+		cfg.RetryBackoff = domain.DefaultRetryBackoff
+	}
+	if cfg.RetryMaxDelay == 0 {
+		cfg.RetryMaxDelay = domain.DefaultRetryMaxDelay
+	}
+	if cfg.RetryAfterMaxWait == 0 {
+		cfg.RetryAfterMaxWait = domain.DefaultRetryAfterMaxWait
+	}
+	return cfg, nil
+}
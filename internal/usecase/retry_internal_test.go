@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/gomic/gomic"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+	"github.com/suzuki-shunsuke/durl/internal/test"
+)
+
+func Test_logicCheckURLWithMethodRetry(t *testing.T) {
+	data := []struct {
+		title       string
+		retryCount  int
+		statusCodes []int
+		attempts    int
+		kind        string
+	}{
+		{"no retry", 0, []int{502, 200}, 1, domain.ErrorKindHTTPServerError},
+		{"recovered", 3, []int{502, 429, 200}, 3, ""},
+		{"retry count is exceeded", 2, []int{503, 503, 503, 200}, 3, domain.ErrorKindHTTPServerError},
+		{"not transient", 3, []int{404, 200}, 1, domain.ErrorKindHTTPClientError},
+	}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			i := 0
+			lgc := &logic{
+				cfg: domain.Cfg{
+					RetryCount: tt.retryCount, RetryBackoff: time.Millisecond, RetryMaxDelay: 2 * time.Millisecond,
+				},
+				client: test.NewHTTPClient(t, gomic.DoNothing).
+					SetFuncDo(func(req *http.Request) (*http.Response, error) {
+						i++
+						return &http.Response{
+							Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+							StatusCode: tt.statusCodes[i-1],
+						}, nil
+					}),
+			}
+			result := lgc.CheckURLWithMethod(context.Background(), "http://example.com", "get")
+			require.Equal(t, tt.kind, result.ErrorKind)
+			require.Equal(t, tt.attempts, result.Attempts)
+			require.Equal(t, tt.attempts, i)
+		})
+	}
+}
+
+func Test_logicGetRetryDelay(t *testing.T) {
+	lgc := &logic{cfg: domain.Cfg{RetryBackoff: time.Second, RetryMaxDelay: 5 * time.Second}}
+	data := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 5 * time.Second},
+		{100, 5 * time.Second},
+	}
+	for _, tt := range data {
+		delay := lgc.getRetryDelay(tt.attempt)
+		require.True(t, delay >= tt.max/2 && delay <= tt.max, delay)
+	}
+}
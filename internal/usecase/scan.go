@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxChunkSize is the max size of a chunk which is read from a file at once.
+	maxChunkSize = 64 * 1024
+	// maxCarrySize is the max size of the text which is carried over to the next chunk of a long line.
+	maxCarrySize = 8 * 1024
+	// separators are characters at which a long line can be split without splitting urls.
+	separators = " \t\"'<>()[]{}`"
+)
+
+// scanLines reads r line by line and calls fn with each line.
+// A line longer than maxChunkSize is split into segments at separators so that memory usage is bounded,
+// and fn is called with each segment.
+// line and col are the line number and the column number (in characters) of the start of the text,
+// which start at 1.
+func scanLines(r io.Reader, fn func(text string, line, col int)) error {
+	reader := bufio.NewReaderSize(r, maxChunkSize)
+	line, col := 1, 1
+	// carry is the end of the previous chunk of the current line which may be a part of a url
+	var carry []byte
+	for {
+		chunk, err := reader.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			buf := append(carry, chunk...) //nolint:gocritic
+			// split the line after the last separator
+			// buf is longer than maxCarrySize because the chunk fills the buffer
+			cut := bytes.LastIndexAny(buf, separators) + 1
+			if len(buf)-cut > maxCarrySize {
+				// there is no separator near the end of the chunk
+				cut = runeBoundary(buf)
+			}
+			fn(string(buf[:cut]), line, col)
+			col += utf8.RuneCount(buf[:cut])
+			carry = append([]byte(nil), buf[cut:]...)
+			continue
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+		buf := append(carry, chunk...) //nolint:gocritic
+		carry = nil
+		if len(buf) != 0 {
+			text := strings.TrimSuffix(strings.TrimSuffix(string(buf), "\n"), "\r")
+			fn(text, line, col)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		line++
+		col = 1
+	}
+}
+
+// runeBoundary returns the length of b without the incomplete rune at the end.
+func runeBoundary(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if utf8.FullRune(b[i:]) {
+				return len(b)
+			}
+			return i
+		}
+	}
+	return len(b)
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_scanLines(t *testing.T) {
+	type segment struct {
+		text string
+		line int
+		col  int
+	}
+	data := []struct {
+		title string
+		in    string
+		exp   []segment
+	}{{
+		"empty", "", nil,
+	}, {
+		"normal", "foo\r\nbar\n\nzoo", []segment{
+			{"foo", 1, 1}, {"bar", 2, 1}, {"", 3, 1}, {"zoo", 4, 1},
+		},
+	}, {
+		"long line without separator", strings.Repeat("X", 140000) + "\nfoo", []segment{
+			{strings.Repeat("X", 65536), 1, 1},
+			{strings.Repeat("X", 65536), 1, 65537},
+			{strings.Repeat("X", 8928), 1, 131073},
+			{"foo", 2, 1},
+		},
+	}}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			var segments []segment
+			err := scanLines(strings.NewReader(tt.in), func(text string, line, col int) {
+				segments = append(segments, segment{text, line, col})
+			})
+			require.Nil(t, err)
+			require.Equal(t, tt.exp, segments)
+		})
+	}
+}
+
+func Test_scanLinesLongLine(t *testing.T) {
+	// a long line is split into segments without splitting urls and characters
+	u := "http://example.com/foo"
+	data := []string{
+		strings.Repeat("あ", 30000) + " " + u + " " + strings.Repeat("い", 30000),
+		strings.Repeat("x ", 32767) + u + strings.Repeat(" x", 40000),
+		strings.Repeat("あ", 21845) + "x" + u + strings.Repeat("x", 60000),
+	}
+	for i, in := range data {
+		joined := ""
+		col := 1
+		found := false
+		err := scanLines(strings.NewReader(in), func(text string, line, c int) {
+			require.Equal(t, 1, line, i)
+			require.Equal(t, col, c, i)
+			require.True(t, utf8.ValidString(text), i)
+			if idx := strings.Index(text, u); idx != -1 && !found {
+				found = true
+				require.Equal(t, strings.Index(in, u), len(joined)+idx, i)
+			}
+			joined += text
+			col += utf8.RuneCountInString(text)
+		})
+		require.Nil(t, err, i)
+		require.Equal(t, in, joined, i)
+		require.True(t, found, i)
+	}
+}
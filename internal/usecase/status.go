@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+// defaultAcceptedStatusCodes are accepted status codes when accepted_status_codes isn't set.
+var defaultAcceptedStatusCodes = []domain.StatusCodeRange{{Min: 200, Max: 299}} //nolint:gochecknoglobals,gomnd
+
+// parseStatusCodes parses status codes such as "200-299,401,403".
+func parseStatusCodes(s string) ([]domain.StatusCodeRange, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	ranges := []domain.StatusCodeRange{}
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		minCode, maxCode := field, field
+		if i := strings.Index(field, "-"); i != -1 {
+			minCode, maxCode = strings.TrimSpace(field[:i]), strings.TrimSpace(field[i+1:])
+		}
+		r := domain.StatusCodeRange{}
+		var err error
+		if r.Min, err = parseStatusCode(minCode); err != nil {
+			return nil, err
+		}
+		if r.Max, err = parseStatusCode(maxCode); err != nil {
+			return nil, err
+		}
+		if r.Min > r.Max {
+			return nil, fmt.Errorf("invalid status code range: %s", field)
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+func parseStatusCode(s string) (int, error) {
+	code, err := strconv.Atoi(s)
+	if err != nil || code < 100 || code > 999 {
+		return 0, fmt.Errorf("invalid status code: %s", s)
+	}
+	return code, nil
+}
+
+// initStatusCodes parses accepted_status_codes and accepted_status_code_rules.
+func initStatusCodes(cfg domain.Cfg) (domain.Cfg, error) {
+	ranges, err := parseStatusCodes(cfg.AcceptedStatusCodes)
+	if err != nil {
+		return cfg, fmt.Errorf("invalid accepted_status_codes: %w", err)
+	}
+	cfg.AcceptedStatusCodeRanges = ranges
+	rules := make([]domain.StatusCodeRule, len(cfg.AcceptedStatusCodeRules))
+	for i, rule := range cfg.AcceptedStatusCodeRules {
+		if rule.Host == "" && rule.URL == "" {
+			return cfg, fmt.Errorf("either host or url is required in accepted_status_code_rules")
+		}
+		rule.Host = strings.ToLower(rule.Host)
+		if rule.URL != "" {
+			reg, err := regexp.Compile(rule.URL)
+			if err != nil {
+				return cfg, fmt.Errorf("invalid url of accepted_status_code_rules: %w", err)
+			}
+			rule.URLPattern = reg
+		}
+		ranges, err := parseStatusCodes(rule.StatusCodes)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid status_codes of accepted_status_code_rules: %w", err)
+		}
+		if ranges == nil {
+			return cfg, fmt.Errorf("status_codes is required in accepted_status_code_rules")
+		}
+		rule.StatusCodeRanges = ranges
+		rules[i] = rule
+	}
+	cfg.AcceptedStatusCodeRules = rules
+	return cfg, nil
+}
+
+// matchStatusCodeRule returns true if the url matches the rule.
+// If the rule has both host and url, the url must match both.
+func matchStatusCodeRule(rule domain.StatusCodeRule, u string) bool {
+	if rule.Host != "" {
+		if host := getHost(u); host != rule.Host && stripPort(host) != rule.Host {
+			return false
+		}
+	}
+	return rule.URLPattern == nil || rule.URLPattern.MatchString(u)
+}
+
+// isAcceptedStatusCode returns true if the status code of the url is accepted.
+// The first rule of accepted_status_code_rules which matches the url is used,
+// and if no rule matches, accepted_status_codes is used.
+func (lgc *logic) isAcceptedStatusCode(u string, code int) bool {
+	ranges := lgc.cfg.AcceptedStatusCodeRanges
+	if ranges == nil {
+		ranges = defaultAcceptedStatusCodes
+	}
+	for _, rule := range lgc.cfg.AcceptedStatusCodeRules {
+		if matchStatusCodeRule(rule, u) {
+			ranges = rule.StatusCodeRanges
+			break
+		}
+	}
+	for _, r := range ranges {
+		if r.Min <= code && code <= r.Max {
+			return true
+		}
+	}
+	return false
+}
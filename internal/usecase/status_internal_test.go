@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/suzuki-shunsuke/gomic/gomic"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+	"github.com/suzuki-shunsuke/durl/internal/test"
+)
+
+func Test_parseStatusCodes(t *testing.T) {
+	data := []struct {
+		in    string
+		exp   []domain.StatusCodeRange
+		isErr bool
+	}{
+		{"", nil, false},
+		{"200-299, 401,403", []domain.StatusCodeRange{{Min: 200, Max: 299}, {Min: 401, Max: 401}, {Min: 403, Max: 403}}, false},
+		{"999", []domain.StatusCodeRange{{Min: 999, Max: 999}}, false},
+		{"299-200", nil, true},
+		{"foo", nil, true},
+		{"200,", nil, true},
+		{"1000", nil, true},
+	}
+	for _, tt := range data {
+		ranges, err := parseStatusCodes(tt.in)
+		if tt.isErr {
+			require.NotNil(t, err, tt.in)
+			continue
+		}
+		require.Nil(t, err, tt.in)
+		require.Equal(t, tt.exp, ranges, tt.in)
+	}
+}
+
+func Test_logicIsAcceptedStatusCode(t *testing.T) {
+	cfg, err := initStatusCodes(domain.Cfg{
+		AcceptedStatusCodes: "200-299,401",
+		AcceptedStatusCodeRules: []domain.StatusCodeRule{
+			{Host: "www.LinkedIn.com", StatusCodes: "200-299,999"},
+			{URL: `^https://example\.com/private/`, StatusCodes: "200-299,403"},
+		},
+	})
+	require.Nil(t, err)
+	lgc := &logic{cfg: cfg}
+	data := []struct {
+		url  string
+		code int
+		exp  bool
+	}{
+		{"https://github.com/foo", 200, true},
+		{"https://github.com/foo", 401, true},
+		{"https://github.com/foo", 403, false},
+		{"https://www.linkedin.com/in/foo", 999, true},
+		{"https://www.linkedin.com:443/in/foo", 999, true},
+		{"https://www.linkedin.com/in/foo", 401, false},
+		{"https://example.com/private/foo", 403, true},
+		{"https://example.com/public/foo", 403, false},
+	}
+	for _, tt := range data {
+		require.Equal(t, tt.exp, lgc.isAcceptedStatusCode(tt.url, tt.code), "%s %d", tt.url, tt.code)
+	}
+	// 2xx is accepted by default
+	lgc = &logic{}
+	require.True(t, lgc.isAcceptedStatusCode("https://github.com", 204))
+	require.False(t, lgc.isAcceptedStatusCode("https://github.com", 403))
+}
+
+func Test_initStatusCodes(t *testing.T) {
+	data := []struct {
+		title string
+		cfg   domain.Cfg
+	}{
+		{"invalid accepted_status_codes", domain.Cfg{AcceptedStatusCodes: "2xx"}},
+		{"neither host nor url", domain.Cfg{AcceptedStatusCodeRules: []domain.StatusCodeRule{{StatusCodes: "403"}}}},
+		{"invalid url", domain.Cfg{AcceptedStatusCodeRules: []domain.StatusCodeRule{{URL: "[", StatusCodes: "403"}}}},
+		{"no status_codes", domain.Cfg{AcceptedStatusCodeRules: []domain.StatusCodeRule{{Host: "github.com"}}}},
+	}
+	for _, tt := range data {
+		_, err := initStatusCodes(tt.cfg)
+		require.NotNil(t, err, tt.title)
+	}
+}
+
+func Test_logicCheckURLWithMethodAcceptedStatusCode(t *testing.T) {
+	lgc := &logic{
+		cfg: domain.Cfg{
+			AcceptedStatusCodeRanges: []domain.StatusCodeRange{{Min: 200, Max: 299}, {Min: 429, Max: 429}},
+			RetryCount:               3,
+		},
+		client: test.NewHTTPClient(t, gomic.DoNothing).
+			SetReturnDo(&http.Response{
+				Body:       ioutil.NopCloser(bytes.NewBufferString("")),
+				StatusCode: 429,
+			}, nil),
+	}
+	result := lgc.CheckURLWithMethod(context.Background(), "http://example.com", "get")
+	require.False(t, result.Failed())
+	// the accepted status code isn't retried
+	require.Equal(t, 1, result.Attempts)
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"os"
+	"regexp"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+var (
+	// variableRegexp matches a placeholder "${VAR}".
+	variableRegexp = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`) //nolint:gochecknoglobals
+	// templateRegexp matches template syntax such as "${VAR}", "{{ .Owner }}" and "<owner>".
+	templateRegexp = regexp.MustCompile(`\$\{|\{\{|\}\}|<[^<>]*>`) //nolint:gochecknoglobals
+)
+
+// expandVariables replaces placeholders "${VAR}" in the url with variables,
+// or environment variables if expand_env is true.
+// A placeholder of an undefined variable is left as it is.
+func (lgc *logic) expandVariables(u string) string {
+	if len(lgc.cfg.Variables) == 0 && !lgc.cfg.ExpandEnv {
+		return u
+	}
+	return variableRegexp.ReplaceAllStringFunc(u, func(placeholder string) string {
+		name := variableRegexp.FindStringSubmatch(placeholder)[1]
+		if v, ok := lgc.cfg.Variables[name]; ok {
+			return v
+		}
+		if lgc.cfg.ExpandEnv {
+			if v, ok := os.LookupEnv(name); ok {
+				return v
+			}
+		}
+		return placeholder
+	})
+}
+
+// isTemplateURL returns true if the url includes template syntax.
+func isTemplateURL(u string) bool {
+	return templateRegexp.MatchString(u)
+}
+
+// expandURLs expands variables in urls and removes urls which still include template syntax,
+// because they aren't real urls.
+func (lgc *logic) expandURLs(urls map[string][]domain.Location) {
+	// expanded url -> locations
+	expandedURLs := map[string][]domain.Location{}
+	for u, locs := range urls {
+		expanded := lgc.expandVariables(u)
+		if expanded == u && !isTemplateURL(u) {
+			continue
+		}
+		delete(urls, u)
+		if !isTemplateURL(expanded) {
+			expandedURLs[expanded] = append(expandedURLs[expanded], locs...)
+		}
+	}
+	for u, locs := range expandedURLs {
+		urls[u] = append(urls[u], locs...)
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/suzuki-shunsuke/durl/internal/domain"
+)
+
+func Test_logicExpandVariables(t *testing.T) {
+	require.Nil(t, os.Setenv("DURL_TEST_VERSION", "1.0.0"))
+	defer os.Unsetenv("DURL_TEST_VERSION")
+	data := []struct {
+		title string
+		url   string
+		cfg   domain.Cfg
+		exp   string
+	}{
+		{
+			"not expanded", "https://github.com/foo/releases/v${DURL_TEST_VERSION}", domain.Cfg{},
+			"https://github.com/foo/releases/v${DURL_TEST_VERSION}",
+		},
+		{
+			"variables", "https://github.com/${OWNER}/releases/v${VERSION}",
+			domain.Cfg{Variables: map[string]string{"OWNER": "foo", "VERSION": "2.0.0"}},
+			"https://github.com/foo/releases/v2.0.0",
+		},
+		{
+			"env", "https://github.com/foo/releases/v${DURL_TEST_VERSION}", domain.Cfg{ExpandEnv: true},
+			"https://github.com/foo/releases/v1.0.0",
+		},
+		{
+			"variables take precedence over env", "https://github.com/foo/releases/v${DURL_TEST_VERSION}",
+			domain.Cfg{ExpandEnv: true, Variables: map[string]string{"DURL_TEST_VERSION": "2.0.0"}},
+			"https://github.com/foo/releases/v2.0.0",
+		},
+		{
+			"undefined", "https://github.com/${DURL_TEST_UNDEFINED}/releases", domain.Cfg{ExpandEnv: true},
+			"https://github.com/${DURL_TEST_UNDEFINED}/releases",
+		},
+	}
+	for _, tt := range data {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			lgc := &logic{cfg: tt.cfg}
+			require.Equal(t, tt.exp, lgc.expandVariables(tt.url))
+		})
+	}
+}
+
+func Test_isTemplateURL(t *testing.T) {
+	data := []struct {
+		url string
+		exp bool
+	}{
+		{"https://github.com/suzuki-shunsuke/durl", false},
+		{"https://github.com/suzuki-shunsuke/durl/releases/v${VERSION}", true},
+		{"https://github.com/{{.Owner}}/durl", true},
+		{"https://github.com/<owner>/durl", true},
+		{"https://github.com/search?q=%7B%7B", false},
+	}
+	for _, tt := range data {
+		require.Equal(t, tt.exp, isTemplateURL(tt.url), tt.url)
+	}
+}
+
+func Test_logicExpandURLs(t *testing.T) {
+	readme := domain.Location{Path: "README.md", Line: 1, Column: 1}
+	guide := domain.Location{Path: "guide.md", Line: 2, Column: 3}
+	lgc := &logic{cfg: domain.Cfg{Variables: map[string]string{"VERSION": "1.0.0"}}}
+	urls := map[string][]domain.Location{
+		"https://github.com/foo/releases/v${VERSION}": {readme},
+		"https://github.com/foo/releases/v1.0.0":      {guide},
+		"https://github.com/${OWNER}/releases":        {readme},
+		"https://github.com/{{.Owner}}/releases":      {readme},
+		"https://github.com/foo":                      {readme},
+	}
+	lgc.expandURLs(urls)
+	require.Equal(t, map[string][]domain.Location{
+		"https://github.com/foo/releases/v1.0.0": {guide, readme},
+		"https://github.com/foo":                 {readme},
+	}, urls)
+}